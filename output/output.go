@@ -3,10 +3,13 @@ package output
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"sort"
 	"strings"
+	"time"
 
+	"github.com/TALLHAMADOU/goleaks/patterns"
 	"github.com/TALLHAMADOU/goleaks/scan"
 
 	"github.com/fatih/color"
@@ -20,6 +23,7 @@ const (
 	FormatJSON     OutputFormat = "json"
 	FormatSARIF    OutputFormat = "sarif"
 	FormatPDF      OutputFormat = "pdf"
+	FormatJSONL    OutputFormat = "jsonl" // Écrit en continu par StreamWriter, pas par PrintResults
 )
 
 // PrintResults affiche les résultats selon le format demandé
@@ -36,24 +40,46 @@ func PrintResults(result *scan.ScanResult, format OutputFormat, verifyLight bool
 	}
 }
 
+// incompleteSummary décrit la cause d'un scan incomplet pour l'affichage texte
+func incompleteSummary(result *scan.ScanResult) string {
+	if result.IncompleteReason == scan.IncompleteTimeout {
+		return "délai dépassé, résultats partiels"
+	}
+	return "scan interrompu, résultats partiels"
+}
+
 // printTerminal affiche les résultats dans le terminal avec couleurs
 func printTerminal(result *scan.ScanResult, _ bool) error {
+	if result.Incomplete {
+		color.Yellow("\n⚠️  Scan incomplet (%s)", incompleteSummary(result))
+	}
 	if len(result.Secrets) == 0 {
 		color.Green("✅ Aucun secret détecté !")
 		return nil
 	}
 
-	// Trier par risque et fichier
-	sort.Slice(result.Secrets, func(i, j int) bool {
-		if result.Secrets[i].Risk != result.Secrets[j].Risk {
-			return result.Secrets[i].Risk == "high"
+	// Trier par sévérité, confiance, fichier et ligne
+	sort.SliceStable(result.Secrets, func(i, j int) bool {
+		a, b := result.Secrets[i], result.Secrets[j]
+		if a.Severity != b.Severity {
+			return a.Severity.Rank() < b.Severity.Rank()
+		}
+		if a.Confidence != b.Confidence {
+			return a.Confidence.Rank() < b.Confidence.Rank()
 		}
-		return result.Secrets[i].File < result.Secrets[j].File
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
 	})
 
-	// Grouper par fichier
+	// Grouper par fichier, dans l'ordre du tri
 	secretsByFile := make(map[string][]scan.Secret)
+	var files []string
 	for _, secret := range result.Secrets {
+		if _, ok := secretsByFile[secret.File]; !ok {
+			files = append(files, secret.File)
+		}
 		secretsByFile[secret.File] = append(secretsByFile[secret.File], secret)
 	}
 
@@ -61,22 +87,40 @@ func printTerminal(result *scan.ScanResult, _ bool) error {
 	color.Yellow(strings.Repeat("━", 80))
 
 	// Afficher les secrets par fichier
-	for file, secrets := range secretsByFile {
+	for _, file := range files {
+		secrets := secretsByFile[file]
 		color.Cyan("\n📄 Fichier: %s", file)
 		for _, secret := range secrets {
 			var riskColor *color.Color
-			switch secret.Risk {
-			case "high":
+			switch secret.Severity {
+			case patterns.SeverityCritical:
+				riskColor = color.New(color.BgRed, color.FgWhite, color.Bold)
+			case patterns.SeverityHigh:
 				riskColor = color.New(color.FgRed, color.Bold)
-			case "medium":
-				riskColor = color.New(color.FgYellow)
+			case patterns.SeverityInfo:
+				riskColor = color.New(color.FgHiBlack)
 			default:
 				riskColor = color.New(color.FgYellow)
 			}
 
-			fmt.Printf("  └─ Ligne %d: ", secret.Line)
-			riskColor.Printf("[%s] ", secret.Risk)
-			color.White("%s - %s\n", secret.Service, secret.Match)
+			fmt.Printf("  └─ %s: ", lineLabel(secret))
+			riskColor.Printf("[%s, confiance %s] ", secret.Severity, secret.Confidence)
+			color.White("%s (%s) - %s\n", secret.Service, secret.RuleID, secret.Match)
+			for _, companion := range secret.Companions {
+				color.White("     + %s (ligne %d): %s\n", companion.Name, companion.Line, companion.Match)
+			}
+			if secret.JWT != nil {
+				color.White("     JWT: %s\n", jwtSummary(secret.JWT))
+			}
+			if secret.URL != nil {
+				color.White("     URL: %s\n", urlSummary(secret.URL))
+			}
+			if secret.Checksum != "" {
+				color.White("     Checksum: %s\n", checksumSummary(secret.Checksum))
+			}
+			if len(secret.Candidates) > 0 {
+				color.White("     Aussi détecté par: %s\n", candidatesSummary(secret.Candidates))
+			}
 			if len(secret.Context) > 0 {
 				color.HiBlack("     Contexte: %s\n", truncate(secret.Context, 100))
 			}
@@ -103,6 +147,14 @@ func printTerminal(result *scan.ScanResult, _ bool) error {
 	return nil
 }
 
+// lineLabel formate la ligne (ou la plage de lignes pour un bloc multi-lignes) d'un secret
+func lineLabel(secret scan.Secret) string {
+	if secret.EndLine > secret.Line {
+		return fmt.Sprintf("Lignes %d-%d", secret.Line, secret.EndLine)
+	}
+	return fmt.Sprintf("Ligne %d", secret.Line)
+}
+
 // truncate tronque une chaîne à une longueur maximale
 func truncate(s string, maxLen int) string {
 	if len(s) <= maxLen {
@@ -114,46 +166,193 @@ func truncate(s string, maxLen int) string {
 // JSONResult structure pour l'export JSON
 type JSONResult struct {
 	Summary struct {
-		TotalSecrets int `json:"total_secrets"`
-		TotalFiles   int `json:"total_files"`
-		ScannedFiles int `json:"scanned_files"`
+		TotalSecrets     int    `json:"total_secrets"`
+		TotalFiles       int    `json:"total_files"`
+		ScannedFiles     int    `json:"scanned_files"`
+		Incomplete       bool   `json:"incomplete,omitempty"`
+		IncompleteReason string `json:"incomplete_reason,omitempty"`
 	} `json:"summary"`
+	Ruleset JSONRuleset  `json:"ruleset"`
 	Secrets []JSONSecret `json:"secrets"`
 	Errors  []string     `json:"errors,omitempty"`
 }
 
+// JSONRuleset structure pour le jeu de règles utilisé par le scan
+type JSONRuleset struct {
+	Version     string            `json:"version"`
+	Packs       []JSONPackVersion `json:"packs"`
+	CustomRules int               `json:"custom_rules,omitempty"`
+}
+
+// JSONPackVersion structure pour un pack de règles intégré et sa version
+type JSONPackVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// newJSONRuleset convertit le jeu de règles d'un scan pour l'export JSON
+func newJSONRuleset(rs patterns.Ruleset) JSONRuleset {
+	j := JSONRuleset{
+		Version:     rs.Version,
+		Packs:       make([]JSONPackVersion, 0, len(rs.Packs)),
+		CustomRules: rs.Custom,
+	}
+	for _, p := range rs.Packs {
+		j.Packs = append(j.Packs, JSONPackVersion{Name: p.Name, Version: p.Version})
+	}
+	return j
+}
+
 // JSONSecret structure pour un secret en JSON
 type JSONSecret struct {
-	File    string `json:"file"`
-	Line    int    `json:"line"`
+	File        string   `json:"file"`
+	Line        int      `json:"line"`
+	EndLine     int      `json:"end_line,omitempty"`
+	RuleID      string   `json:"rule_id"`
+	Service     string   `json:"service"`
+	Description string   `json:"description,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	CWE         string   `json:"cwe,omitempty"`
+	DocsURL     string   `json:"docs_url,omitempty"`
+	Match       string   `json:"match"`
+	Entropy     float64  `json:"entropy"`
+	Severity    string   `json:"severity"`
+	Risk        string   `json:"risk"` // Ancien nom de severity, conservé pour compatibilité
+	Confidence  string   `json:"confidence"`
+	Checksum    string   `json:"checksum,omitempty"`
+	Context     string   `json:"context"`
+
+	Companions []JSONCompanion `json:"companions,omitempty"`
+	JWT        *JSONJWT        `json:"jwt,omitempty"`
+	URL        *JSONURL        `json:"url,omitempty"`
+	Candidates []JSONCandidate `json:"candidates,omitempty"`
+}
+
+// JSONCandidate structure pour une autre règle ayant détecté le même secret en JSON
+type JSONCandidate struct {
+	RuleID  string `json:"rule_id"`
 	Service string `json:"service"`
-	Match   string `json:"match"`
-	Risk    string `json:"risk"`
-	Context string `json:"context"`
+}
+
+// newJSONCandidates convertit les règles candidates d'un secret (nil si aucune)
+func newJSONCandidates(candidates []scan.Candidate) []JSONCandidate {
+	var list []JSONCandidate
+	for _, c := range candidates {
+		list = append(list, JSONCandidate{RuleID: c.RuleID, Service: c.Service})
+	}
+	return list
+}
+
+// JSONCompanion structure pour un secret compagnon en JSON
+type JSONCompanion struct {
+	Name  string `json:"name"`
+	Line  int    `json:"line"`
+	Match string `json:"match"`
+}
+
+// JSONJWT structure pour les claims d'un JWT décodé (signature non vérifiée)
+type JSONJWT struct {
+	Alg       string   `json:"alg,omitempty"`
+	Iss       string   `json:"iss,omitempty"`
+	Role      string   `json:"role,omitempty"`
+	Exp       int64    `json:"exp,omitempty"`
+	ExpiresAt string   `json:"expires_at,omitempty"`
+	Aud       []string `json:"aud,omitempty"`
+	Expired   bool     `json:"expired"`
+}
+
+// newJSONJWT convertit les claims d'un secret pour l'export JSON et SARIF
+func newJSONJWT(claims *scan.JWTClaims) *JSONJWT {
+	if claims == nil {
+		return nil
+	}
+	j := &JSONJWT{
+		Alg:     claims.Alg,
+		Iss:     claims.Iss,
+		Role:    claims.Role,
+		Exp:     claims.Exp,
+		Aud:     claims.Aud,
+		Expired: claims.Expired,
+	}
+	if claims.Exp != 0 {
+		j.ExpiresAt = claims.ExpiresAt().Format(time.RFC3339)
+	}
+	return j
+}
+
+// JSONURL structure pour l'URL contenant un identifiant
+type JSONURL struct {
+	Scheme    string `json:"scheme"`
+	Host      string `json:"host"`
+	Username  string `json:"username,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+}
+
+// newJSONURL convertit l'URL d'un secret pour l'export JSON et SARIF
+func newJSONURL(u *scan.URLCredential) *JSONURL {
+	if u == nil {
+		return nil
+	}
+	return &JSONURL{
+		Scheme:    u.Scheme,
+		Host:      u.Host,
+		Username:  u.Username,
+		Parameter: u.Parameter,
+	}
+}
+
+// newJSONSecret convertit un secret pour l'export JSON et JSON Lines
+func newJSONSecret(secret scan.Secret) JSONSecret {
+	var companions []JSONCompanion
+	for _, companion := range secret.Companions {
+		companions = append(companions, JSONCompanion{
+			Name:  companion.Name,
+			Line:  companion.Line,
+			Match: companion.Match,
+		})
+	}
+	return JSONSecret{
+		File:        secret.File,
+		Line:        secret.Line,
+		EndLine:     secret.EndLine,
+		RuleID:      secret.RuleID,
+		Service:     secret.Service,
+		Description: secret.Description,
+		Tags:        secret.Tags,
+		CWE:         secret.CWE,
+		DocsURL:     secret.DocsURL,
+		Match:       secret.Match,
+		Entropy:     roundEntropy(secret.Entropy),
+		Severity:    string(secret.Severity),
+		Risk:        string(secret.Severity),
+		Confidence:  string(secret.Confidence),
+		Checksum:    secret.Checksum,
+		Context:     secret.Context,
+		Companions:  companions,
+		JWT:         newJSONJWT(secret.JWT),
+		URL:         newJSONURL(secret.URL),
+		Candidates:  newJSONCandidates(secret.Candidates),
+	}
 }
 
 // printJSON affiche les résultats en format JSON
 func printJSON(result *scan.ScanResult) error {
 	jsonResult := JSONResult{
+		Ruleset: newJSONRuleset(result.Ruleset),
 		Secrets: make([]JSONSecret, 0, len(result.Secrets)),
 		Errors:  result.Errors,
 	}
 
 	jsonResult.Summary.TotalSecrets = len(result.Secrets)
 	jsonResult.Summary.ScannedFiles = result.Files
+	jsonResult.Summary.Incomplete = result.Incomplete
+	jsonResult.Summary.IncompleteReason = result.IncompleteReason
 
 	// Compter les fichiers uniques
 	filesMap := make(map[string]bool)
 	for _, secret := range result.Secrets {
 		filesMap[secret.File] = true
-		jsonResult.Secrets = append(jsonResult.Secrets, JSONSecret{
-			File:    secret.File,
-			Line:    secret.Line,
-			Service: secret.Service,
-			Match:   secret.Match,
-			Risk:    secret.Risk,
-			Context: secret.Context,
-		})
+		jsonResult.Secrets = append(jsonResult.Secrets, newJSONSecret(secret))
 	}
 	jsonResult.Summary.TotalFiles = len(filesMap)
 
@@ -164,17 +363,65 @@ func printJSON(result *scan.ScanResult) error {
 
 // SARIFResult structure pour l'export SARIF (format standard pour CI/CD)
 type SARIFResult struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun représente une exécution de l'outil
+type SARIFRun struct {
+	Tool struct {
+		Driver     SARIFDriver      `json:"driver"`
+		Extensions []SARIFExtension `json:"extensions,omitempty"`
+	} `json:"tool"`
+	Invocations []SARIFInvocation `json:"invocations"`
+	Results     []SARIFResultItem `json:"results"`
+}
+
+// SARIFInvocation décrit l'exécution du scan: un scan interrompu n'a pas réussi
+type SARIFInvocation struct {
+	ExecutionSuccessful        bool                `json:"executionSuccessful"`
+	ToolExecutionNotifications []SARIFNotification `json:"toolExecutionNotifications,omitempty"`
+}
+
+// SARIFNotification est un message de l'outil sur son exécution
+type SARIFNotification struct {
+	Level   string `json:"level"`
+	Message struct {
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// SARIFDriver décrit l'outil et les règles utilisées
+type SARIFDriver struct {
+	Name       string      `json:"name"`
+	Version    string      `json:"version"`
+	Rules      []SARIFRule `json:"rules,omitempty"`
+	Properties struct {
+		RulesetVersion string `json:"rulesetVersion,omitempty"`
+	} `json:"properties"`
+}
+
+// SARIFExtension décrit un pack de règles intégré utilisé par le scan
+type SARIFExtension struct {
+	Name    string `json:"name"`
 	Version string `json:"version"`
-	Schema  string `json:"$schema"`
-	Runs    []struct {
-		Tool struct {
-			Driver struct {
-				Name    string `json:"name"`
-				Version string `json:"version"`
-			} `json:"driver"`
-		} `json:"tool"`
-		Results []SARIFResultItem `json:"results"`
-	} `json:"runs"`
+}
+
+// SARIFRule décrit une règle de détection (reportingDescriptor)
+type SARIFRule struct {
+	ID               string `json:"id"`
+	Name             string `json:"name"`
+	ShortDescription struct {
+		Text string `json:"text"`
+	} `json:"shortDescription"`
+	HelpURI    string `json:"helpUri,omitempty"`
+	Properties struct {
+		Tags             []string `json:"tags,omitempty"`
+		CWE              string   `json:"cwe,omitempty"`
+		SecuritySeverity string   `json:"security-severity,omitempty"`
+		Precision        string   `json:"precision,omitempty"`
+	} `json:"properties"`
 }
 
 // SARIFResultItem représente un résultat SARIF
@@ -184,96 +431,99 @@ type SARIFResultItem struct {
 	Message struct {
 		Text string `json:"text"`
 	} `json:"message"`
-	Locations []struct {
-		PhysicalLocation struct {
-			ArtifactLocation struct {
-				URI string `json:"uri"`
-			} `json:"artifactLocation"`
-			Region struct {
-				StartLine int `json:"startLine"`
-			} `json:"region"`
-		} `json:"physicalLocation"`
-	} `json:"locations"`
+	Locations  []SARIFLocation `json:"locations"`
+	Properties struct {
+		Severity   string          `json:"severity"`
+		Confidence string          `json:"confidence"`
+		Entropy    float64         `json:"entropy"`
+		Checksum   string          `json:"checksum,omitempty"`
+		JWT        *JSONJWT        `json:"jwt,omitempty"`
+		URL        *JSONURL        `json:"url,omitempty"`
+		Candidates []JSONCandidate `json:"candidates,omitempty"`
+	} `json:"properties"`
+}
+
+// SARIFLocation représente l'emplacement d'un résultat
+type SARIFLocation struct {
+	PhysicalLocation struct {
+		ArtifactLocation struct {
+			URI string `json:"uri"`
+		} `json:"artifactLocation"`
+		Region struct {
+			StartLine int `json:"startLine"`
+			EndLine   int `json:"endLine,omitempty"`
+		} `json:"region"`
+	} `json:"physicalLocation"`
 }
 
 // printSARIF affiche les résultats en format SARIF
 func printSARIF(result *scan.ScanResult) error {
-	sarif := SARIFResult{
-		Version: "2.1.0",
-		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
-		Runs: []struct {
-			Tool struct {
-				Driver struct {
-					Name    string `json:"name"`
-					Version string `json:"version"`
-				} `json:"driver"`
-			} `json:"tool"`
-			Results []SARIFResultItem `json:"results"`
-		}{
-			{
-				Tool: struct {
-					Driver struct {
-						Name    string `json:"name"`
-						Version string `json:"version"`
-					} `json:"driver"`
-				}{
-					Driver: struct {
-						Name    string `json:"name"`
-						Version string `json:"version"`
-					}{
-						Name:    "SecretHunter",
-						Version: "1.0.0",
-					},
-				},
-				Results: make([]SARIFResultItem, 0, len(result.Secrets)),
-			},
-		},
+	run := SARIFRun{
+		Results: make([]SARIFResultItem, 0, len(result.Secrets)),
+	}
+	run.Tool.Driver = SARIFDriver{
+		Name:    "SecretHunter",
+		Version: "1.0.0",
+	}
+	run.Tool.Driver.Properties.RulesetVersion = result.Ruleset.Version
+	for _, p := range result.Ruleset.Packs {
+		run.Tool.Extensions = append(run.Tool.Extensions, SARIFExtension{Name: "goleaks-rules-" + p.Name, Version: p.Version})
 	}
 
+	// Une règle SARIF par règle de détection, avec la sévérité et la confiance de son secret le plus grave
+	ruleIndex := make(map[string]int)
+	ruleSeverity := make(map[string]patterns.Severity)
 	for _, secret := range result.Secrets {
-		level := "warning"
-		if secret.Risk == "high" {
-			level = "error"
+		i, seen := ruleIndex[secret.RuleID]
+		if !seen {
+			ruleIndex[secret.RuleID] = len(run.Tool.Driver.Rules)
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule(secret))
+			ruleSeverity[secret.RuleID] = secret.Severity
+		} else if secret.Severity.Rank() < ruleSeverity[secret.RuleID].Rank() {
+			ruleSeverity[secret.RuleID] = secret.Severity
+			run.Tool.Driver.Rules[i].Properties.SecuritySeverity = securitySeverity(secret.Severity)
+			run.Tool.Driver.Rules[i].Properties.Precision = sarifPrecision(secret.Confidence)
 		}
 
+		level := sarifLevel(secret)
+
+		var location SARIFLocation
+		location.PhysicalLocation.ArtifactLocation.URI = secret.File
+		location.PhysicalLocation.Region.StartLine = secret.Line
+		location.PhysicalLocation.Region.EndLine = secret.EndLine
+
 		item := SARIFResultItem{
-			RuleID: fmt.Sprintf("%s-secret", strings.ToLower(secret.Service)),
-			Level:  level,
-			Locations: []struct {
-				PhysicalLocation struct {
-					ArtifactLocation struct {
-						URI string `json:"uri"`
-					} `json:"artifactLocation"`
-					Region struct {
-						StartLine int `json:"startLine"`
-					} `json:"region"`
-				} `json:"physicalLocation"`
-			}{
-				{
-					PhysicalLocation: struct {
-						ArtifactLocation struct {
-							URI string `json:"uri"`
-						} `json:"artifactLocation"`
-						Region struct {
-							StartLine int `json:"startLine"`
-						} `json:"region"`
-					}{
-						ArtifactLocation: struct {
-							URI string `json:"uri"`
-						}{
-							URI: secret.File,
-						},
-						Region: struct {
-							StartLine int `json:"startLine"`
-						}{
-							StartLine: secret.Line,
-						},
-					},
-				},
-			},
+			RuleID:    secret.RuleID,
+			Level:     level,
+			Locations: []SARIFLocation{location},
 		}
 		item.Message.Text = fmt.Sprintf("Secret %s détecté: %s", secret.Service, secret.Match)
-		sarif.Runs[0].Results = append(sarif.Runs[0].Results, item)
+		item.Properties.Severity = string(secret.Severity)
+		item.Properties.Confidence = string(secret.Confidence)
+		item.Properties.Entropy = roundEntropy(secret.Entropy)
+		item.Properties.Checksum = secret.Checksum
+		item.Properties.JWT = newJSONJWT(secret.JWT)
+		item.Properties.URL = newJSONURL(secret.URL)
+		item.Properties.Candidates = newJSONCandidates(secret.Candidates)
+		for _, companion := range secret.Companions {
+			item.Message.Text += fmt.Sprintf(" + %s (ligne %d): %s", companion.Name, companion.Line, companion.Match)
+		}
+		run.Results = append(run.Results, item)
+	}
+
+	invocation := SARIFInvocation{ExecutionSuccessful: !result.Incomplete}
+	if result.Incomplete {
+		var notification SARIFNotification
+		notification.Level = "warning"
+		notification.Message.Text = "Scan incomplet (" + incompleteSummary(result) + ")"
+		invocation.ToolExecutionNotifications = append(invocation.ToolExecutionNotifications, notification)
+	}
+	run.Invocations = []SARIFInvocation{invocation}
+
+	sarif := SARIFResult{
+		Version: "2.1.0",
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Runs:    []SARIFRun{run},
 	}
 
 	encoder := json.NewEncoder(os.Stdout)
@@ -281,6 +531,120 @@ func printSARIF(result *scan.ScanResult) error {
 	return encoder.Encode(sarif)
 }
 
+// jwtSummary résume les claims d'un JWT pour l'affichage texte
+func jwtSummary(claims *scan.JWTClaims) string {
+	var parts []string
+	if claims.Alg != "" {
+		parts = append(parts, "alg="+claims.Alg)
+	}
+	if claims.Iss != "" {
+		parts = append(parts, "iss="+claims.Iss)
+	}
+	if claims.Role != "" {
+		parts = append(parts, "role="+claims.Role)
+	}
+	if len(claims.Aud) > 0 {
+		parts = append(parts, "aud="+strings.Join(claims.Aud, ","))
+	}
+	if claims.Exp != 0 {
+		exp := "exp=" + claims.ExpiresAt().Format(time.RFC3339)
+		if claims.Expired {
+			exp += " (expiré)"
+		}
+		parts = append(parts, exp)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// candidatesSummary liste les autres règles ayant détecté un secret (ex: "Asana (asana-token)")
+func candidatesSummary(candidates []scan.Candidate) string {
+	parts := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		parts = append(parts, fmt.Sprintf("%s (%s)", c.Service, c.RuleID))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// checksumSummary décrit le statut du checksum d'un secret pour l'affichage texte
+func checksumSummary(status string) string {
+	if status == scan.ChecksumValid {
+		return "valide (confiance élevée)"
+	}
+	return "invalide (sévérité et confiance abaissées)"
+}
+
+// urlSummary résume l'URL contenant un identifiant pour l'affichage texte
+func urlSummary(u *scan.URLCredential) string {
+	parts := []string{"scheme=" + u.Scheme, "host=" + u.Host}
+	if u.Username != "" {
+		parts = append(parts, "user="+u.Username)
+	}
+	if u.Parameter != "" {
+		parts = append(parts, "param="+u.Parameter)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// roundEntropy arrondit une entropie à deux décimales pour l'export
+func roundEntropy(entropy float64) float64 {
+	return math.Round(entropy*100) / 100
+}
+
+// sarifRule construit la description SARIF de la règle ayant produit un secret
+func sarifRule(secret scan.Secret) SARIFRule {
+	rule := SARIFRule{
+		ID:      secret.RuleID,
+		Name:    secret.Service,
+		HelpURI: secret.DocsURL,
+	}
+	rule.ShortDescription.Text = secret.Description
+	if rule.ShortDescription.Text == "" {
+		rule.ShortDescription.Text = fmt.Sprintf("Secret %s", secret.Service)
+	}
+	rule.Properties.Tags = secret.Tags
+	rule.Properties.CWE = secret.CWE
+	rule.Properties.SecuritySeverity = securitySeverity(secret.Severity)
+	rule.Properties.Precision = sarifPrecision(secret.Confidence)
+	return rule
+}
+
+// securitySeverityScores associe chaque sévérité au score "security-severity" (0 à 10)
+// utilisé par GitHub code scanning: critical > 9, high >= 7, medium >= 4, low > 0
+var securitySeverityScores = map[patterns.Severity]string{
+	patterns.SeverityCritical: "9.5",
+	patterns.SeverityHigh:     "8.0",
+	patterns.SeverityMedium:   "5.5",
+	patterns.SeverityLow:      "3.0",
+	patterns.SeverityInfo:     "0.0",
+}
+
+// securitySeverity retourne le score SARIF "security-severity" d'une sévérité
+func securitySeverity(severity patterns.Severity) string {
+	return securitySeverityScores[severity]
+}
+
+// sarifPrecision retourne la précision SARIF (high, medium, low) d'une confiance
+func sarifPrecision(confidence patterns.Confidence) string {
+	return string(confidence)
+}
+
+// sarifLevel retourne le niveau SARIF d'un secret: error pour critical et high,
+// warning pour medium et low, note pour info; une confiance faible abaisse le niveau d'un cran
+func sarifLevel(secret scan.Secret) string {
+	levels := []string{"error", "warning", "note"}
+	level := 1
+	switch secret.Severity {
+	case patterns.SeverityCritical, patterns.SeverityHigh:
+		level = 0
+	case patterns.SeverityInfo:
+		level = 2
+	}
+	if secret.Confidence == patterns.ConfidenceLow && level < 2 {
+		level++
+	}
+	return levels[level]
+}
+
 // printPDF génère un PDF basique (pour l'instant, affiche un message)
 func printPDF(result *scan.ScanResult) error {
 	// TODO: Implémenter génération PDF avec bibliothèque comme github.com/jung-kurt/gofpdf
@@ -294,17 +658,46 @@ func printPDF(result *scan.ScanResult) error {
 	fmt.Println("=" + strings.Repeat("=", 78) + "=")
 	fmt.Printf("\nDate: %s\n", "2026")
 	fmt.Printf("Fichiers scannés: %d\n", result.Files)
-	fmt.Printf("Secrets détectés: %d\n\n", len(result.Secrets))
+	fmt.Printf("Secrets détectés: %d\n", len(result.Secrets))
+	if result.Incomplete {
+		fmt.Printf("Scan incomplet: %s\n", incompleteSummary(result))
+	}
+	fmt.Println()
 
 	if len(result.Secrets) > 0 {
 		fmt.Println("DÉTAILS DES SECRETS DÉTECTÉS:")
 		fmt.Println(strings.Repeat("-", 80))
 		for _, secret := range result.Secrets {
 			fmt.Printf("\nFichier: %s\n", secret.File)
-			fmt.Printf("Ligne: %d\n", secret.Line)
+			fmt.Printf("%s\n", lineLabel(secret))
 			fmt.Printf("Service: %s\n", secret.Service)
-			fmt.Printf("Risque: %s\n", secret.Risk)
+			fmt.Printf("Règle: %s\n", secret.RuleID)
+			if secret.Description != "" {
+				fmt.Printf("Description: %s\n", secret.Description)
+			}
+			fmt.Printf("Sévérité: %s (confiance %s)\n", secret.Severity, secret.Confidence)
+			if secret.CWE != "" {
+				fmt.Printf("CWE: %s\n", secret.CWE)
+			}
+			if secret.DocsURL != "" {
+				fmt.Printf("Remédiation: %s\n", secret.DocsURL)
+			}
 			fmt.Printf("Match: %s\n", secret.Match)
+			for _, companion := range secret.Companions {
+				fmt.Printf("Compagnon: %s (ligne %d): %s\n", companion.Name, companion.Line, companion.Match)
+			}
+			if secret.JWT != nil {
+				fmt.Printf("JWT: %s\n", jwtSummary(secret.JWT))
+			}
+			if secret.URL != nil {
+				fmt.Printf("URL: %s\n", urlSummary(secret.URL))
+			}
+			if secret.Checksum != "" {
+				fmt.Printf("Checksum: %s\n", checksumSummary(secret.Checksum))
+			}
+			if len(secret.Candidates) > 0 {
+				fmt.Printf("Aussi détecté par: %s\n", candidatesSummary(secret.Candidates))
+			}
 			if secret.Context != "" {
 				fmt.Printf("Contexte: %s\n", secret.Context)
 			}
@@ -0,0 +1,87 @@
+package output
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+	"github.com/TALLHAMADOU/goleaks/scan"
+)
+
+// La clé JSON risk, ancien nom de severity, reste présente
+func TestJSONSecretSeverity(t *testing.T) {
+	secret := scan.Secret{RuleID: "r", Severity: patterns.SeverityCritical, Confidence: patterns.ConfidenceHigh}
+	data, err := json.Marshal(newJSONSecret(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`"severity":"critical"`, `"risk":"critical"`, `"confidence":"high"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s ne contient pas %s", data, want)
+		}
+	}
+}
+
+func TestSARIFSeverityMapping(t *testing.T) {
+	tests := []struct {
+		severity   patterns.Severity
+		confidence patterns.Confidence
+		level      string
+		score      string
+	}{
+		{patterns.SeverityCritical, patterns.ConfidenceHigh, "error", "9.5"},
+		{patterns.SeverityHigh, patterns.ConfidenceMedium, "error", "8.0"},
+		{patterns.SeverityHigh, patterns.ConfidenceLow, "warning", "8.0"},
+		{patterns.SeverityMedium, patterns.ConfidenceMedium, "warning", "5.5"},
+		{patterns.SeverityLow, patterns.ConfidenceLow, "note", "3.0"},
+		{patterns.SeverityInfo, patterns.ConfidenceHigh, "note", "0.0"},
+	}
+	for _, tt := range tests {
+		secret := scan.Secret{Severity: tt.severity, Confidence: tt.confidence}
+		if got := sarifLevel(secret); got != tt.level {
+			t.Errorf("%s/%s: niveau %s, attendu %s", tt.severity, tt.confidence, got, tt.level)
+		}
+		if got := securitySeverity(tt.severity); got != tt.score {
+			t.Errorf("%s: security-severity %s, attendu %s", tt.severity, got, tt.score)
+		}
+	}
+}
+
+// Le statut du checksum figure dans le JSON, et seulement s'il a été vérifié
+func TestJSONSecretChecksum(t *testing.T) {
+	for status, want := range map[string]string{
+		scan.ChecksumValid:   `"checksum":"valid"`,
+		scan.ChecksumInvalid: `"checksum":"invalid"`,
+		"":                   "",
+	} {
+		data, err := json.Marshal(newJSONSecret(scan.Secret{RuleID: "r", Checksum: status}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(string(data), `"checksum"`); got != (want != "") || !strings.Contains(string(data), want) {
+			t.Errorf("checksum %q: %s", status, data)
+		}
+	}
+}
+
+// Les claims d'un JWT décodé sont exportés avec la date d'expiration lisible
+func TestJSONSecretJWT(t *testing.T) {
+	secret := scan.Secret{RuleID: "supabase-jwt", JWT: &scan.JWTClaims{Alg: "HS256", Iss: "supabase", Role: "anon", Exp: 2000000000, Aud: []string{"authenticated"}}}
+	data, err := json.Marshal(newJSONSecret(secret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `"jwt":{"alg":"HS256","iss":"supabase","role":"anon","exp":2000000000,"expires_at":"2033-05-18T03:33:20Z","aud":["authenticated"],"expired":false}`
+	if !strings.Contains(string(data), want) {
+		t.Errorf("%s ne contient pas %s", data, want)
+	}
+
+	data, err = json.Marshal(newJSONSecret(scan.Secret{RuleID: "r"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"jwt"`) {
+		t.Errorf("%s: clé jwt sans JWT décodé", data)
+	}
+}
@@ -0,0 +1,78 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/TALLHAMADOU/goleaks/scan"
+)
+
+// JSONLEvent est une ligne de la sortie JSON Lines
+type JSONLEvent struct {
+	Type    string        `json:"type"` // "finding", "error" ou "summary" (dernière ligne)
+	Secret  *JSONSecret   `json:"secret,omitempty"`
+	Error   string        `json:"error,omitempty"`
+	Summary *JSONLSummary `json:"summary,omitempty"`
+}
+
+// JSONLSummary structure pour le résumé qui termine la sortie JSON Lines
+type JSONLSummary struct {
+	TotalSecrets     int         `json:"total_secrets"`
+	ScannedFiles     int         `json:"scanned_files"`
+	Errors           int         `json:"errors"`
+	Incomplete       bool        `json:"incomplete,omitempty"`
+	IncompleteReason string      `json:"incomplete_reason,omitempty"`
+	Ruleset          JSONRuleset `json:"ruleset"`
+}
+
+// StreamWriter écrit les résultats au format JSON Lines au fur et à mesure du scan:
+// une ligne par secret ou erreur, sans les conserver, puis une ligne de résumé
+type StreamWriter struct {
+	encoder *json.Encoder
+	secrets int
+	errors  int
+	err     error // Première erreur d'écriture
+}
+
+// NewStreamWriter crée un StreamWriter
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{encoder: json.NewEncoder(w)}
+}
+
+// Finding écrit un secret (utilisable comme scan.FindingHandler)
+func (w *StreamWriter) Finding(secret scan.Secret) {
+	j := newJSONSecret(secret)
+	w.secrets++
+	w.write(JSONLEvent{Type: "finding", Secret: &j})
+}
+
+// Error écrit une erreur de fichier
+func (w *StreamWriter) Error(message string) {
+	w.errors++
+	w.write(JSONLEvent{Type: "error", Error: message})
+}
+
+// Secrets retourne le nombre de secrets écrits
+func (w *StreamWriter) Secrets() int {
+	return w.secrets
+}
+
+// Close écrit le résumé du scan (fichiers scannés, règles, interruption) et
+// retourne la première erreur d'écriture
+func (w *StreamWriter) Close(result *scan.ScanResult) error {
+	w.write(JSONLEvent{Type: "summary", Summary: &JSONLSummary{
+		TotalSecrets:     w.secrets,
+		ScannedFiles:     result.Files,
+		Errors:           w.errors,
+		Incomplete:       result.Incomplete,
+		IncompleteReason: result.IncompleteReason,
+		Ruleset:          newJSONRuleset(result.Ruleset),
+	}})
+	return w.err
+}
+
+func (w *StreamWriter) write(event JSONLEvent) {
+	if w.err == nil {
+		w.err = w.encoder.Encode(event)
+	}
+}
@@ -0,0 +1,53 @@
+package output
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+	"github.com/TALLHAMADOU/goleaks/scan"
+)
+
+// Une ligne par secret et par erreur, puis le résumé
+func TestStreamWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewStreamWriter(&buf)
+	w.Finding(scan.Secret{File: "a.env", Line: 1, RuleID: "r", Severity: patterns.SeverityHigh})
+	w.Error("Erreur scan b.env: délai par fichier dépassé")
+	w.Finding(scan.Secret{File: "c.env", Line: 2, RuleID: "r", Severity: patterns.SeverityLow})
+	if err := w.Close(&scan.ScanResult{Files: 3, Incomplete: true, IncompleteReason: scan.IncompleteCanceled}); err != nil {
+		t.Fatal(err)
+	}
+	if w.Secrets() != 2 {
+		t.Errorf("%d secrets écrits, attendu 2", w.Secrets())
+	}
+
+	var events []JSONLEvent
+	lines := bufio.NewScanner(&buf)
+	for lines.Scan() {
+		var event JSONLEvent
+		if err := json.Unmarshal(lines.Bytes(), &event); err != nil {
+			t.Fatalf("ligne invalide %q: %v", lines.Text(), err)
+		}
+		events = append(events, event)
+	}
+
+	types := []string{"finding", "error", "finding", "summary"}
+	if len(events) != len(types) {
+		t.Fatalf("%d lignes, attendu %d", len(events), len(types))
+	}
+	for i, event := range events {
+		if event.Type != types[i] {
+			t.Errorf("ligne %d: type %s, attendu %s", i+1, event.Type, types[i])
+		}
+	}
+	if s := events[0].Secret; s == nil || s.File != "a.env" || s.Severity != "high" {
+		t.Errorf("secret %+v", s)
+	}
+	summary := events[3].Summary
+	if summary == nil || summary.TotalSecrets != 2 || summary.Errors != 1 || summary.ScannedFiles != 3 || !summary.Incomplete {
+		t.Errorf("résumé %+v", summary)
+	}
+}
@@ -0,0 +1,163 @@
+package patterns
+
+import (
+	"fmt"
+	"hash/crc32"
+	"strconv"
+	"strings"
+)
+
+// Validateurs de checksum hors ligne disponibles pour une règle (champ Validator)
+const (
+	// ValidatorGitHub vérifie les tokens GitHub préfixés (ghp_, gho_, ghu_, ghs_):
+	// les 6 derniers caractères sont le CRC32 en base62 des 30 caractères aléatoires
+	ValidatorGitHub = "github"
+	// ValidatorNPM vérifie les tokens npm (npm_), construits comme les tokens GitHub
+	ValidatorNPM = "npm"
+	// ValidatorLuhn vérifie la clé de Luhn d'un numéro de carte bancaire
+	ValidatorLuhn = "luhn"
+	// ValidatorIBAN vérifie la clé (modulo 97) d'un IBAN
+	ValidatorIBAN = "iban"
+	// ValidatorNIR vérifie la clé d'un numéro de sécurité sociale français
+	ValidatorNIR = "nir"
+)
+
+// Validators liste les validateurs de checksum disponibles
+var Validators = []string{ValidatorGitHub, ValidatorNPM, ValidatorLuhn, ValidatorIBAN, ValidatorNIR}
+
+// IsKnownValidator indique si un nom de validateur est reconnu
+func IsKnownValidator(name string) bool {
+	for _, v := range Validators {
+		if v == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsStrictValidator indique si un checksum incorrect écarte toujours le secret:
+// un numéro (carte, IBAN, NIR) dont la clé est fausse n'est qu'une suite de chiffres
+func IsStrictValidator(name string) bool {
+	return name == ValidatorLuhn || name == ValidatorIBAN || name == ValidatorNIR
+}
+
+// ValidChecksum vérifie le checksum d'un secret avec le validateur nommé
+func ValidChecksum(validator, secret string) bool {
+	switch validator {
+	case ValidatorGitHub, ValidatorNPM:
+		return validCRC32Base62(secret)
+	case ValidatorLuhn:
+		return validLuhn(stripSeparators(secret))
+	case ValidatorIBAN:
+		return validIBAN(stripSeparators(secret))
+	case ValidatorNIR:
+		return validNIR(stripSeparators(secret))
+	default:
+		return false
+	}
+}
+
+// crc32BodyLength est la longueur de la partie aléatoire couverte par le checksum
+const crc32BodyLength = 30
+
+// validCRC32Base62 vérifie un token de la forme <préfixe>_<30 caractères><CRC32 en base62 sur 6 caractères>
+func validCRC32Base62(token string) bool {
+	rest := token[strings.LastIndexByte(token, '_')+1:]
+	if len(rest) != crc32BodyLength+6 {
+		return false
+	}
+	return rest[crc32BodyLength:] == crc32Base62(rest[:crc32BodyLength])
+}
+
+// base62 est l'alphabet des checksums GitHub et npm
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// crc32Base62 encode le CRC32 d'une chaîne en base62 sur 6 caractères (complété par des zéros)
+func crc32Base62(s string) string {
+	n := crc32.ChecksumIEEE([]byte(s))
+	out := []byte("000000")
+	for i := len(out) - 1; i >= 0 && n > 0; i-- {
+		out[i] = base62[n%62]
+		n /= 62
+	}
+	return string(out)
+}
+
+// stripSeparators retire les espaces et tirets qui groupent les chiffres d'un numéro
+func stripSeparators(s string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(s)
+}
+
+// validLuhn vérifie la clé de Luhn d'une suite de chiffres
+func validLuhn(number string) bool {
+	if len(number) < 2 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < len(number); i++ {
+		d := int(number[len(number)-1-i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// validIBAN vérifie un IBAN: les 4 premiers caractères sont déplacés à la fin,
+// les lettres converties en nombres (A = 10) et le tout doit valoir 1 modulo 97
+func validIBAN(iban string) bool {
+	iban = strings.ToUpper(iban)
+	if len(iban) < 15 || len(iban) > 34 {
+		return false
+	}
+	return mod97(ibanDigits(iban[4:]+iban[:4])) == 1
+}
+
+// ibanDigits convertit les lettres d'un IBAN en nombres (A = 10, ..., Z = 35)
+func ibanDigits(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			b.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			b.WriteString(strconv.Itoa(int(c-'A') + 10))
+		default:
+			return ""
+		}
+	}
+	return b.String()
+}
+
+// validNIR vérifie un numéro de sécurité sociale français: 13 chiffres puis une clé
+// égale à 97 moins le numéro modulo 97 (la Corse, 2A et 2B, compte pour 19 et 18)
+func validNIR(nir string) bool {
+	if len(nir) != 15 {
+		return false
+	}
+	number := strings.NewReplacer("2A", "19", "2B", "18").Replace(strings.ToUpper(nir[:13]))
+	remainder := mod97(number)
+	return remainder >= 0 && nir[13:] == fmt.Sprintf("%02d", 97-remainder)
+}
+
+// mod97 calcule le reste de la division par 97 d'un nombre écrit en décimal (-1 si invalide)
+func mod97(number string) int {
+	if number == "" {
+		return -1
+	}
+	remainder := 0
+	for i := 0; i < len(number); i++ {
+		d := int(number[i] - '0')
+		if d < 0 || d > 9 {
+			return -1
+		}
+		remainder = (remainder*10 + d) % 97
+	}
+	return remainder
+}
@@ -0,0 +1,69 @@
+package patterns
+
+import "testing"
+
+func TestValidChecksum(t *testing.T) {
+	valid := fakeChecksumToken("ghp_")
+	// Un caractère modifié dans la partie aléatoire invalide le checksum
+	tampered := valid[:10] + string(rune(valid[10])^1) + valid[11:]
+
+	tests := []struct {
+		validator string
+		token     string
+		want      bool
+	}{
+		{ValidatorGitHub, valid, true},
+		{ValidatorGitHub, fakeChecksumToken("gho_"), true},
+		{ValidatorNPM, fakeChecksumToken("npm_"), true},
+		{ValidatorGitHub, tampered, false},
+		{ValidatorGitHub, "ghp_" + fakeSecret(alnum, 36), false},
+		{ValidatorGitHub, valid[:len(valid)-1], false}, // Longueur incorrecte
+		{ValidatorGitHub, "ghp_", false},
+		{"inconnu", valid, false},
+	}
+	for _, tt := range tests {
+		if got := ValidChecksum(tt.validator, tt.token); got != tt.want {
+			t.Errorf("ValidChecksum(%s, %q) = %v, attendu %v", tt.validator, tt.token, got, tt.want)
+		}
+	}
+}
+
+func TestCRC32Base62(t *testing.T) {
+	// Valeur de référence: CRC32 IEEE de "" = 0, complété par des zéros
+	if got := crc32Base62(""); got != "000000" {
+		t.Errorf("crc32Base62(\"\") = %q, attendu 000000", got)
+	}
+	// CRC32 IEEE de "a" = 0xe8b7be43 = 3904355907
+	if got := crc32Base62("a"); got != "4GEHKN" {
+		t.Errorf("crc32Base62(\"a\") = %q", got)
+	}
+}
+
+func TestValidChecksumPII(t *testing.T) {
+	tests := []struct {
+		validator string
+		number    string
+		want      bool
+	}{
+		{ValidatorLuhn, "4111 1111 1111 1111", true},
+		{ValidatorLuhn, "3782-822463-10005", true},
+		{ValidatorLuhn, "4111 1111 1111 1112", false},
+		{ValidatorLuhn, "4111 1111 1111 111x", false},
+		{ValidatorLuhn, fakeLuhn("4539"), true},
+		{ValidatorIBAN, "FR14 2004 1010 0505 0001 3M02 606", true},
+		{ValidatorIBAN, "GB82WEST12345698765432", true},
+		{ValidatorIBAN, "GB82WEST12345698765433", false},
+		{ValidatorIBAN, "FR14", false},
+		{ValidatorIBAN, fakeIBAN("DE"), true},
+		{ValidatorNIR, "2 55 08 14 168 025 38", true},
+		{ValidatorNIR, "185052A12345633", true}, // Corse: 2A compte pour 19
+		{ValidatorNIR, "255081416802539", false},
+		{ValidatorNIR, "25508141680253", false},
+		{ValidatorNIR, fakeNIR("2"), true},
+	}
+	for _, tt := range tests {
+		if got := ValidChecksum(tt.validator, tt.number); got != tt.want {
+			t.Errorf("ValidChecksum(%s, %q) = %v, attendu %v", tt.validator, tt.number, got, tt.want)
+		}
+	}
+}
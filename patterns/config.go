@@ -0,0 +1,521 @@
+package patterns
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Modes de combinaison des règles chargées avec les patterns intégrés
+const (
+	ModeMerge   = "merge"   // Ajoute les règles (et remplace celles de même identifiant)
+	ModeReplace = "replace" // Remplace entièrement les patterns intégrés
+)
+
+// RuleConfig représente une règle de détection telle qu'écrite dans un fichier TOML ou YAML
+type RuleConfig struct {
+	ID                 string            `toml:"id,omitempty" yaml:"id,omitempty"`
+	Service            string            `toml:"service" yaml:"service"`
+	Description        string            `toml:"description,omitempty" yaml:"description,omitempty"`
+	Tags               []string          `toml:"tags,omitempty" yaml:"tags,omitempty"`
+	CWE                string            `toml:"cwe,omitempty" yaml:"cwe,omitempty"`
+	DocsURL            string            `toml:"docs_url,omitempty" yaml:"docs_url,omitempty"`
+	Keywords           []string          `toml:"keywords,omitempty" yaml:"keywords,omitempty"`
+	Regex              string            `toml:"regex" yaml:"regex"`
+	SecretGroup        int               `toml:"secret_group,omitzero" yaml:"secret_group,omitempty"`
+	Entropy            float64           `toml:"entropy,omitzero" yaml:"entropy,omitempty"`
+	EntropyThresholds  EntropyConfig     `toml:"entropy_thresholds,omitempty" yaml:"entropy_thresholds,omitempty"`
+	RejectPlaceholders bool              `toml:"reject_placeholders,omitempty" yaml:"reject_placeholders,omitempty"`
+	Fallback           bool              `toml:"fallback,omitempty" yaml:"fallback,omitempty"`
+	EndRegex           string            `toml:"end_regex,omitempty" yaml:"end_regex,omitempty"`
+	Companions         []CompanionConfig `toml:"companions,omitempty" yaml:"companions,omitempty"`
+	Paths              []string          `toml:"paths,omitempty" yaml:"paths,omitempty"`
+	PathRegex          string            `toml:"path_regex,omitempty" yaml:"path_regex,omitempty"`
+	FileTypes          []string          `toml:"file_types,omitempty" yaml:"file_types,omitempty"`
+	Allowlist          AllowlistConfig   `toml:"allowlist,omitempty" yaml:"allowlist,omitempty"`
+	Analyzer           string            `toml:"analyzer,omitempty" yaml:"analyzer,omitempty"`
+	Validator          string            `toml:"validator,omitempty" yaml:"validator,omitempty"`
+	Severity           string            `toml:"severity,omitempty" yaml:"severity,omitempty"`
+	Risk               string            `toml:"risk,omitempty" yaml:"risk,omitempty"` // Ancien nom de severity
+	Confidence         string            `toml:"confidence,omitempty" yaml:"confidence,omitempty"`
+	HighRisk           bool              `toml:"high_risk,omitempty" yaml:"high_risk,omitempty"`
+	VerifyURL          string            `toml:"verify_url,omitempty" yaml:"verify_url,omitempty"`
+	VerifyHeader       string            `toml:"verify_header,omitempty" yaml:"verify_header,omitempty"`
+	Examples           []string          `toml:"examples,omitempty" yaml:"examples,omitempty"`
+	NegativeExamples   []string          `toml:"negative_examples,omitempty" yaml:"negative_examples,omitempty"`
+}
+
+// CompanionConfig représente un secret compagnon d'une règle composite
+type CompanionConfig struct {
+	Name        string   `toml:"name" yaml:"name"`
+	Keywords    []string `toml:"keywords,omitempty" yaml:"keywords,omitempty"`
+	Regex       string   `toml:"regex" yaml:"regex"`
+	Required    bool     `toml:"required,omitempty" yaml:"required,omitempty"`
+	WithinLines int      `toml:"within_lines,omitzero" yaml:"within_lines,omitempty"`
+}
+
+// Compile valide un compagnon et le transforme en Companion
+func (cc CompanionConfig) Compile() (Companion, error) {
+	if strings.TrimSpace(cc.Name) == "" {
+		return Companion{}, fmt.Errorf("champ 'name' manquant")
+	}
+	if cc.Regex == "" {
+		return Companion{}, fmt.Errorf("champ 'regex' manquant")
+	}
+	re, err := regexp.Compile(cc.Regex)
+	if err != nil {
+		return Companion{}, fmt.Errorf("regex invalide %q: %v", cc.Regex, err)
+	}
+	if cc.WithinLines < 0 {
+		return Companion{}, fmt.Errorf("within_lines négatif: %d", cc.WithinLines)
+	}
+	return Companion{
+		Name:        cc.Name,
+		Keywords:    cc.Keywords,
+		Regex:       re,
+		Required:    cc.Required,
+		WithinLines: cc.WithinLines,
+	}, nil
+}
+
+// EntropyConfig représente les seuils d'entropie normalisée (entre 0 et 1) d'une règle
+type EntropyConfig struct {
+	Hex          float64 `toml:"hex,omitzero" yaml:"hex,omitempty"`
+	Base64       float64 `toml:"base64,omitzero" yaml:"base64,omitempty"`
+	Alphanumeric float64 `toml:"alphanumeric,omitzero" yaml:"alphanumeric,omitempty"`
+	Other        float64 `toml:"other,omitzero" yaml:"other,omitempty"`
+}
+
+// Compile valide les seuils d'entropie
+func (ec EntropyConfig) Compile() (EntropyThresholds, error) {
+	t := EntropyThresholds(ec)
+	for _, v := range []float64{t.Hex, t.Base64, t.Alphanumeric, t.Other} {
+		if v < 0 || v > 1 {
+			return EntropyThresholds{}, fmt.Errorf("entropy_thresholds: seuil %v hors de l'intervalle [0, 1]", v)
+		}
+	}
+	return t, nil
+}
+
+// AllowlistConfig représente l'allowlist d'une règle dans un fichier de configuration
+type AllowlistConfig struct {
+	Regexes     []string `toml:"regexes,omitempty" yaml:"regexes,omitempty"`
+	Stopwords   []string `toml:"stopwords,omitempty" yaml:"stopwords,omitempty"`
+	Paths       []string `toml:"paths,omitempty" yaml:"paths,omitempty"`
+	PathRegexes []string `toml:"path_regexes,omitempty" yaml:"path_regexes,omitempty"`
+}
+
+// Compile valide une allowlist et compile ses regex
+func (ac AllowlistConfig) Compile() (Allowlist, error) {
+	allowlist := Allowlist{
+		Stopwords: ac.Stopwords,
+		Paths:     ac.Paths,
+	}
+	for _, expr := range ac.Regexes {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return Allowlist{}, fmt.Errorf("allowlist: regex invalide %q: %v", expr, err)
+		}
+		allowlist.Regexes = append(allowlist.Regexes, re)
+	}
+	for _, expr := range ac.PathRegexes {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return Allowlist{}, fmt.Errorf("allowlist: regex de chemin invalide %q: %v", expr, err)
+		}
+		allowlist.PathRegex = append(allowlist.PathRegex, re)
+	}
+	if err := validateGlobs(ac.Paths); err != nil {
+		return Allowlist{}, fmt.Errorf("allowlist: %v", err)
+	}
+	return allowlist, nil
+}
+
+// validateGlobs vérifie la syntaxe d'une liste de globs
+func validateGlobs(globs []string) error {
+	for _, glob := range globs {
+		if _, err := path.Match(glob, ""); err != nil {
+			return fmt.Errorf("glob invalide %q: %v", glob, err)
+		}
+	}
+	return nil
+}
+
+// RuleFile représente le contenu d'un fichier de règles
+type RuleFile struct {
+	Rules []RuleConfig `toml:"rules" yaml:"rules"`
+}
+
+// Compile valide une règle et la transforme en Pattern
+func (rc RuleConfig) Compile() (Pattern, error) {
+	if strings.TrimSpace(rc.Service) == "" {
+		return Pattern{}, fmt.Errorf("champ 'service' manquant")
+	}
+	if rc.Regex == "" {
+		return Pattern{}, fmt.Errorf("champ 'regex' manquant")
+	}
+
+	re, err := regexp.Compile(rc.Regex)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("regex invalide %q: %v", rc.Regex, err)
+	}
+
+	if rc.SecretGroup < 0 || rc.SecretGroup > re.NumSubexp() {
+		return Pattern{}, fmt.Errorf("secret_group %d hors limites (la regex a %d groupe(s))", rc.SecretGroup, re.NumSubexp())
+	}
+	if rc.Entropy < 0 {
+		return Pattern{}, fmt.Errorf("entropy négative: %v", rc.Entropy)
+	}
+	thresholds, err := rc.EntropyThresholds.Compile()
+	if err != nil {
+		return Pattern{}, err
+	}
+
+	var pathRe *regexp.Regexp
+	if rc.PathRegex != "" {
+		pathRe, err = regexp.Compile(rc.PathRegex)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("path_regex invalide %q: %v", rc.PathRegex, err)
+		}
+	}
+
+	var endRe *regexp.Regexp
+	if rc.EndRegex != "" {
+		endRe, err = regexp.Compile(rc.EndRegex)
+		if err != nil {
+			return Pattern{}, fmt.Errorf("end_regex invalide %q: %v", rc.EndRegex, err)
+		}
+	}
+
+	companions := make([]Companion, 0, len(rc.Companions))
+	for i, cc := range rc.Companions {
+		companion, err := cc.Compile()
+		if err != nil {
+			return Pattern{}, fmt.Errorf("compagnon #%d: %v", i+1, err)
+		}
+		companions = append(companions, companion)
+	}
+	if len(companions) > 0 && endRe != nil {
+		return Pattern{}, fmt.Errorf("une règle multi-lignes ne peut pas déclarer de compagnons")
+	}
+
+	if err := validateGlobs(rc.Paths); err != nil {
+		return Pattern{}, err
+	}
+	allowlist, err := rc.Allowlist.Compile()
+	if err != nil {
+		return Pattern{}, err
+	}
+
+	severity, confidence, err := rc.levels()
+	if err != nil {
+		return Pattern{}, err
+	}
+	if rc.VerifyURL != "" && !strings.HasPrefix(rc.VerifyURL, "https://") {
+		return Pattern{}, fmt.Errorf("verify_url doit être une URL https: %q", rc.VerifyURL)
+	}
+	if rc.VerifyHeader != "" && !strings.Contains(rc.VerifyHeader, ":") {
+		return Pattern{}, fmt.Errorf("verify_header invalide %q (attendu: \"Nom: valeur {secret}\")", rc.VerifyHeader)
+	}
+	if rc.Analyzer != "" && !IsKnownAnalyzer(rc.Analyzer) {
+		return Pattern{}, fmt.Errorf("analyseur inconnu %q (attendu: %s)", rc.Analyzer, strings.Join(Analyzers, ", "))
+	}
+	if rc.Validator != "" && !IsKnownValidator(rc.Validator) {
+		return Pattern{}, fmt.Errorf("validateur inconnu %q (attendu: %s)", rc.Validator, strings.Join(Validators, ", "))
+	}
+
+	examples, err := expandExamples(rc.Examples)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("examples: %v", err)
+	}
+	negativeExamples, err := expandExamples(rc.NegativeExamples)
+	if err != nil {
+		return Pattern{}, fmt.Errorf("negative_examples: %v", err)
+	}
+
+	id := strings.TrimSpace(rc.ID)
+	if id == "" {
+		id = RuleID(rc.Service)
+	}
+	cwe := strings.TrimSpace(rc.CWE)
+	if cwe == "" {
+		cwe = DefaultCWE
+	}
+
+	return Pattern{
+		ID:                 id,
+		Service:            rc.Service,
+		Description:        rc.Description,
+		Tags:               rc.Tags,
+		CWE:                cwe,
+		DocsURL:            rc.DocsURL,
+		Keywords:           rc.Keywords,
+		Regex:              re,
+		SecretGroup:        rc.SecretGroup,
+		Entropy:            rc.Entropy,
+		EntropyThresholds:  thresholds,
+		RejectPlaceholders: rc.RejectPlaceholders,
+		Fallback:           rc.Fallback,
+		PathRegex:          pathRe,
+		EndRegex:           endRe,
+		Companions:         companions,
+		Paths:              rc.Paths,
+		FileTypes:          rc.FileTypes,
+		Allowlist:          allowlist,
+		Analyzer:           rc.Analyzer,
+		Validator:          rc.Validator,
+		Severity:           severity,
+		Risk:               string(severity),
+		Confidence:         confidence,
+		IsHighRisk:         rc.HighRisk,
+		VerifyURL:          rc.VerifyURL,
+		VerifyHeader:       rc.VerifyHeader,
+		Examples:           examples,
+		NegativeExamples:   negativeExamples,
+	}, nil
+}
+
+// levels retourne la sévérité (champ severity, ou risk) et la confiance d'une règle.
+// Par défaut: sévérité medium, confiance low pour une règle générique et medium sinon.
+func (rc RuleConfig) levels() (Severity, Confidence, error) {
+	severity, confidence := SeverityMedium, ConfidenceMedium
+	if rc.Fallback {
+		confidence = ConfidenceLow
+	}
+
+	value := rc.Severity
+	if strings.TrimSpace(value) == "" {
+		value = rc.Risk
+	}
+	if strings.TrimSpace(value) != "" {
+		parsed, err := ParseSeverity(value)
+		if err != nil {
+			return "", "", err
+		}
+		severity = parsed
+	}
+	if strings.TrimSpace(rc.Confidence) != "" {
+		parsed, err := ParseConfidence(rc.Confidence)
+		if err != nil {
+			return "", "", err
+		}
+		confidence = parsed
+	}
+	return severity, confidence, nil
+}
+
+// expandExamples remplace les générateurs des exemples d'une règle (voir ExpandExample)
+func expandExamples(list []string) ([]string, error) {
+	if len(list) == 0 {
+		return nil, nil
+	}
+	expanded := make([]string, 0, len(list))
+	for _, example := range list {
+		value, err := ExpandExample(example)
+		if err != nil {
+			return nil, err
+		}
+		expanded = append(expanded, value)
+	}
+	return expanded, nil
+}
+
+// CompileRules compile une liste de règles, en indiquant la règle fautive en cas d'erreur
+func CompileRules(rules []RuleConfig) ([]Pattern, error) {
+	compiled := make([]Pattern, 0, len(rules))
+	seen := make(map[string]int, len(rules))
+	for i, rc := range rules {
+		p, err := rc.Compile()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", ruleLabel(i, rc), err)
+		}
+		if first, ok := seen[p.ID]; ok {
+			return nil, fmt.Errorf("%s: identifiant %q déjà utilisé par la règle #%d", ruleLabel(i, rc), p.ID, first)
+		}
+		seen[p.ID] = i + 1
+		compiled = append(compiled, p)
+	}
+	return compiled, nil
+}
+
+// ruleLabel identifie une règle dans les messages d'erreur
+func ruleLabel(i int, rc RuleConfig) string {
+	switch {
+	case rc.ID != "":
+		return fmt.Sprintf("règle #%d (%s)", i+1, rc.ID)
+	case rc.Service != "":
+		return fmt.Sprintf("règle #%d (%s)", i+1, rc.Service)
+	default:
+		return fmt.Sprintf("règle #%d", i+1)
+	}
+}
+
+// ParseRuleFile décode un fichier de règles au format "toml" ou "yaml"
+func ParseRuleFile(data []byte, format string) (*RuleFile, error) {
+	var rf RuleFile
+	switch strings.ToLower(format) {
+	case "toml":
+		if _, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&rf); err != nil {
+			return nil, err
+		}
+	case "yaml", "yml":
+		if err := yaml.Unmarshal(data, &rf); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("format de règles non supporté: %s", format)
+	}
+	return &rf, nil
+}
+
+// FormatFromPath déduit le format d'un fichier de règles depuis son extension
+func FormatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return "toml", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	default:
+		return "", fmt.Errorf("extension non supportée pour %s (attendu: .toml, .yaml, .yml)", path)
+	}
+}
+
+// LoadRulesFile charge et compile les règles d'un fichier TOML ou YAML
+func LoadRulesFile(path string) ([]Pattern, error) {
+	return LoadRulesFileFormat(path, "")
+}
+
+// LoadRulesFileFormat charge et compile les règles d'un fichier. Le format
+// "gitleaks" importe un .gitleaks.toml; sinon le format natif est déduit de l'extension.
+func LoadRulesFileFormat(path string, format string) ([]Pattern, error) {
+	rf, _, err := ReadRuleFile(path, format)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled, err := CompileRules(rf.Rules)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return compiled, nil
+}
+
+// ExtendAllowlist ajoute une allowlist à la règle d'identifiant donné
+func ExtendAllowlist(list []Pattern, id string, allowlist Allowlist) ([]Pattern, error) {
+	extended := append([]Pattern(nil), list...)
+	for i := range extended {
+		if extended[i].ID == id {
+			extended[i].Allowlist = extended[i].Allowlist.Merge(allowlist)
+			return extended, nil
+		}
+	}
+	return nil, fmt.Errorf("règle inconnue: %s", id)
+}
+
+// ReadRuleFile lit un fichier de règles sans le compiler, en convertissant le
+// format gitleaks si demandé. Retourne aussi les avertissements de conversion.
+func ReadRuleFile(path string, format string) (*RuleFile, []string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("lecture du fichier de règles: %v", err)
+	}
+
+	format = strings.ToLower(strings.TrimSpace(format))
+	switch format {
+	case FormatGitleaks:
+		rf, warnings, err := ImportGitleaks(data)
+		if err != nil {
+			return nil, warnings, fmt.Errorf("%s: %v", path, err)
+		}
+		return rf, warnings, nil
+	case "", "goleaks":
+		format, err = FormatFromPath(path)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	rf, err := ParseRuleFile(data, format)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return rf, nil, nil
+}
+
+// EncodeRuleFile écrit un fichier de règles au format "toml" ou "yaml"
+func EncodeRuleFile(rf *RuleFile, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	switch strings.ToLower(format) {
+	case "toml":
+		if err := toml.NewEncoder(&buf).Encode(rf); err != nil {
+			return nil, err
+		}
+	case "yaml", "yml":
+		encoder := yaml.NewEncoder(&buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(rf); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("format de règles non supporté: %s", format)
+	}
+	return buf.Bytes(), nil
+}
+
+// RejectVerifyEndpoints retourne une erreur si une règle déclare un point de
+// vérification (verify_url, verify_header): --verify-light y enverrait les secrets
+// trouvés. Seuls les packs intégrés en déclarent, sauf autorisation explicite.
+func RejectVerifyEndpoints(list []Pattern) error {
+	for _, p := range list {
+		if p.VerifyURL != "" || p.VerifyHeader != "" {
+			return fmt.Errorf("règle %s: verify_url et verify_header sont réservés aux règles intégrées (--allow-custom-verify pour les autoriser)", p.ID)
+		}
+	}
+	return nil
+}
+
+// Combine fusionne des patterns personnalisés avec une base selon le mode demandé.
+// En mode merge, un pattern personnalisé remplace le pattern de base de même identifiant.
+// Un pattern qui reprend l'identifiant d'un pattern de base garde son point de
+// vérification (verify_url, verify_header), quel que soit le mode.
+func Combine(base, custom []Pattern, mode string) ([]Pattern, error) {
+	switch mode {
+	case ModeReplace, ModeMerge, "":
+	default:
+		return nil, fmt.Errorf("mode de règles invalide %q (attendu: %s, %s)", mode, ModeMerge, ModeReplace)
+	}
+
+	builtin := make(map[string]Pattern, len(base))
+	for _, p := range base {
+		builtin[p.ID] = p
+	}
+	custom = append([]Pattern(nil), custom...)
+	for i := range custom {
+		if b, ok := builtin[custom[i].ID]; ok {
+			custom[i].VerifyURL, custom[i].VerifyHeader = b.VerifyURL, b.VerifyHeader
+		}
+	}
+	if mode == ModeReplace {
+		return custom, nil
+	}
+
+	overrides := make(map[string]bool, len(custom))
+	for _, p := range custom {
+		overrides[p.ID] = true
+	}
+
+	merged := make([]Pattern, 0, len(base)+len(custom))
+	for _, p := range base {
+		if !overrides[p.ID] {
+			merged = append(merged, p)
+		}
+	}
+	return append(merged, custom...), nil
+}
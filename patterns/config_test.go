@@ -0,0 +1,162 @@
+package patterns
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+const tomlRules = `
+[[rules]]
+id = "acme-key"
+service = "Acme"
+keywords = ["acme_"]
+regex = '\bacme_[a-z0-9]{24}\b'
+severity = "high"
+tags = ["saas"]
+
+[[rules]]
+id = "acme-secret"
+service = "Acme"
+regex = 'ACME_SECRET=(?P<secret>[A-Za-z0-9]{32})'
+`
+
+const yamlRules = `
+rules:
+  - id: acme-key
+    service: Acme
+    keywords: [acme_]
+    regex: '\bacme_[a-z0-9]{24}\b'
+    severity: high
+    tags: [saas]
+  - id: acme-secret
+    service: Acme
+    regex: 'ACME_SECRET=(?P<secret>[A-Za-z0-9]{32})'
+`
+
+// Les formats TOML et YAML décrivent les mêmes règles
+func TestParseRuleFile(t *testing.T) {
+	fromTOML, err := ParseRuleFile([]byte(tomlRules), "toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := ParseRuleFile([]byte(yamlRules), "yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromTOML, fromYAML) {
+		t.Errorf("TOML %+v\nYAML %+v", fromTOML.Rules, fromYAML.Rules)
+	}
+
+	list, err := CompileRules(fromTOML.Rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].ID != "acme-key" || list[0].Severity != SeverityHigh || list[1].Regex.SubexpIndex(SecretGroup) != 1 {
+		t.Errorf("règles compilées %+v", list)
+	}
+
+	if _, err := ParseRuleFile([]byte(tomlRules), "json"); err == nil {
+		t.Error("format json accepté")
+	}
+}
+
+func TestLoadRulesFile(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name    string
+		content string
+		want    string // Erreur attendue (vide = succès)
+	}{
+		{"rules.toml", tomlRules, ""},
+		{"rules.yaml", yamlRules, ""},
+		{"rules.yml", yamlRules, ""},
+		{"rules.json", "{}", "extension non supportée"},
+		{"broken.toml", "[[rules]\n", "broken.toml"},
+		{"bad.yaml", "rules:\n  - id: bad\n    service: Bad\n    regex: '('\n", "bad.yaml: règle #1 (bad): regex invalide"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		list, err := LoadRulesFile(path)
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: %v", tt.name, err)
+		case tt.want == "" && len(list) != 2:
+			t.Errorf("%s: %d règles, attendu 2", tt.name, len(list))
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: erreur %v, attendu %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+// Une règle invalide est désignée par sa position et son identifiant
+func TestCompileRulesErrors(t *testing.T) {
+	valid := RuleConfig{ID: "ok", Service: "Ok", Regex: `ok_[a-z]{8}`}
+	tests := []struct {
+		name string
+		rule RuleConfig
+		want string
+	}{
+		{"regex", RuleConfig{ID: "bad-regex", Service: "Bad", Regex: `(`}, "règle #2 (bad-regex): regex invalide"},
+		{"service", RuleConfig{ID: "no-service", Regex: `x`}, "règle #2 (no-service): champ 'service' manquant"},
+		{"sans id", RuleConfig{Service: "Acme"}, "règle #2 (Acme): champ 'regex' manquant"},
+		{"groupe", RuleConfig{ID: "group", Service: "G", Regex: `a(b)`, SecretGroup: 2}, "règle #2 (group): secret_group 2 hors limites"},
+		{"doublon", RuleConfig{ID: "ok", Service: "Ok", Regex: `x`}, `règle #2 (ok): identifiant "ok" déjà utilisé par la règle #1`},
+		{"sévérité", RuleConfig{ID: "sev", Service: "S", Regex: `x`, Severity: "urgent"}, "règle #2 (sev)"},
+	}
+	for _, tt := range tests {
+		_, err := CompileRules([]RuleConfig{valid, tt.rule})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: erreur %v, attendu %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestCombine(t *testing.T) {
+	re := regexp.MustCompile("x")
+	base := []Pattern{
+		{ID: "a", Service: "A", Regex: re},
+		{ID: "b", Service: "B", Regex: re, VerifyURL: "https://api.example/b"},
+	}
+	custom := []Pattern{
+		{ID: "b", Service: "B personnalisé", Regex: re},
+		{ID: "c", Service: "C", Regex: re},
+	}
+	tests := []struct {
+		mode string
+		want string
+	}{
+		{"", "a:A,b:B personnalisé,c:C"},
+		{ModeMerge, "a:A,b:B personnalisé,c:C"},
+		{ModeReplace, "b:B personnalisé,c:C"},
+	}
+	for _, tt := range tests {
+		combined, err := Combine(base, custom, tt.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, p := range combined {
+			got = append(got, p.ID+":"+p.Service)
+			if p.ID == "b" && p.VerifyURL != "https://api.example/b" {
+				t.Errorf("mode %q: verify_url de b %q", tt.mode, p.VerifyURL)
+			}
+		}
+		if strings.Join(got, ",") != tt.want {
+			t.Errorf("mode %q: %s, attendu %s", tt.mode, strings.Join(got, ","), tt.want)
+		}
+	}
+
+	if _, err := Combine(base, custom, "append"); err == nil {
+		t.Error("mode append accepté")
+	}
+	if len(custom[0].VerifyURL) != 0 {
+		t.Error("Combine modifie les patterns personnalisés reçus")
+	}
+}
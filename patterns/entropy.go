@@ -0,0 +1,149 @@
+package patterns
+
+import (
+	"math"
+	"strings"
+)
+
+// Jeux de caractères distingués pour les seuils d'entropie
+const (
+	CharsetHex          = "hex"
+	CharsetBase64       = "base64"
+	CharsetAlphanumeric = "alphanumeric"
+	CharsetOther        = "other"
+)
+
+// charsetSizes donne le nombre de symboles de chaque jeu de caractères
+var charsetSizes = map[string]int{
+	CharsetHex:          16,
+	CharsetBase64:       64,
+	CharsetAlphanumeric: 62,
+	CharsetOther:        95, // ASCII imprimable
+}
+
+// EntropyThresholds définit l'entropie normalisée minimale (entre 0 et 1) d'un
+// secret selon son jeu de caractères. Un seuil à 0 désactive le contrôle.
+type EntropyThresholds struct {
+	Hex          float64
+	Base64       float64
+	Alphanumeric float64
+	Other        float64
+}
+
+// DefaultEntropyThresholds sont les seuils appliqués en mode smart
+var DefaultEntropyThresholds = EntropyThresholds{
+	Hex:          0.7,
+	Base64:       0.7,
+	Alphanumeric: 0.7,
+	Other:        0.6,
+}
+
+// IsZero indique si aucun seuil n'est défini
+func (t EntropyThresholds) IsZero() bool {
+	return t == EntropyThresholds{}
+}
+
+// WithDefaults complète les seuils non définis avec ceux d'un autre jeu de seuils
+func (t EntropyThresholds) WithDefaults(d EntropyThresholds) EntropyThresholds {
+	if t.Hex == 0 {
+		t.Hex = d.Hex
+	}
+	if t.Base64 == 0 {
+		t.Base64 = d.Base64
+	}
+	if t.Alphanumeric == 0 {
+		t.Alphanumeric = d.Alphanumeric
+	}
+	if t.Other == 0 {
+		t.Other = d.Other
+	}
+	return t
+}
+
+// For retourne le seuil d'un jeu de caractères
+func (t EntropyThresholds) For(charset string) float64 {
+	switch charset {
+	case CharsetHex:
+		return t.Hex
+	case CharsetBase64:
+		return t.Base64
+	case CharsetAlphanumeric:
+		return t.Alphanumeric
+	default:
+		return t.Other
+	}
+}
+
+// Allows indique si l'entropie normalisée d'une chaîne atteint le seuil de son jeu de caractères
+func (t EntropyThresholds) Allows(s string) bool {
+	threshold := t.For(Charset(s))
+	return threshold == 0 || NormalizedEntropy(s) >= threshold
+}
+
+// ShannonEntropy calcule l'entropie de Shannon d'une chaîne, en bits par caractère
+func ShannonEntropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+
+	freq := make(map[rune]int)
+	total := 0
+	for _, char := range s {
+		freq[char]++
+		total++
+	}
+
+	entropy := 0.0
+	length := float64(total)
+	for _, count := range freq {
+		p := float64(count) / length
+		entropy -= p * math.Log2(p)
+	}
+
+	return entropy
+}
+
+// Charset détermine le plus petit jeu de caractères contenant la chaîne
+func Charset(s string) string {
+	hex, alphanumeric := true, true
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f', c >= 'A' && c <= 'F':
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+			hex = false
+		case strings.IndexByte("+/=-_", c) >= 0:
+			hex, alphanumeric = false, false
+		default:
+			return CharsetOther
+		}
+	}
+
+	// Les minuscules et majuscules mélangées ne sont pas de l'hexadécimal usuel
+	if hex && strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		hex = false
+	}
+
+	switch {
+	case hex:
+		return CharsetHex
+	case alphanumeric:
+		return CharsetAlphanumeric
+	default:
+		return CharsetBase64
+	}
+}
+
+// NormalizedEntropy rapporte l'entropie de Shannon d'une chaîne au maximum
+// atteignable pour sa longueur et son jeu de caractères, log2(min(longueur, taille)).
+// Le résultat, entre 0 et 1, est comparable entre chaînes courtes et longues.
+func NormalizedEntropy(s string) float64 {
+	size := charsetSizes[Charset(s)]
+	if n := len(s); n < size {
+		size = n
+	}
+	if size < 2 {
+		return 0
+	}
+	return ShannonEntropy(s) / math.Log2(float64(size))
+}
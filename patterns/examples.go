@@ -0,0 +1,184 @@
+package patterns
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Jeux de caractères utilisés pour générer les exemples des patterns intégrés
+const (
+	alnum      = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	lowerAlnum = "abcdefghijklmnopqrstuvwxyz0123456789"
+	upperAlnum = "ABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	lowerHex   = "0123456789abcdef"
+	upperHex   = "0123456789ABCDEF"
+	digits     = "0123456789"
+	base64URL  = alnum + "-_"
+	base64Std  = alnum + "+/"
+)
+
+// fakeSecret génère une valeur déterministe de n caractères pour les exemples.
+// Les secrets d'exemple ne sont jamais écrits en clair dans le code source, afin
+// de ne pas déclencher les scanners de secrets (y compris goleaks lui-même).
+func fakeSecret(charset string, n int) string {
+	var b strings.Builder
+	b.Grow(n)
+	for i := 0; i < n; i++ {
+		b.WriteByte(charset[(i*i*7+i*13+5)%len(charset)])
+	}
+	return b.String()
+}
+
+// fakeJWT construit un JWT d'exemple (signature factice) à partir de ses claims JSON
+func fakeJWT(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(claims)) + "." + fakeSecret(base64URL, 43)
+}
+
+// fakeBlock construit un bloc ASCII armor d'exemple (en-tête, corps base64, pied)
+func fakeBlock(label string) string {
+	body := fakeSecret(base64Std, 192)
+	return "-----BEGIN " + label + "-----\n" + body[:64] + "\n" + body[64:128] + "\n" + body[128:] + "\n-----END " + label + "-----"
+}
+
+// fakeUUID génère un UUID d'exemple déterministe
+func fakeUUID(seed int) string {
+	hex := fakeSecret(lowerHex, 32+seed)[seed:]
+	return hex[:8] + "-" + hex[8:12] + "-" + hex[12:16] + "-" + hex[16:20] + "-" + hex[20:]
+}
+
+// fakeChecksumToken génère un token d'exemple dont le checksum CRC32/base62 est valide
+func fakeChecksumToken(prefix string) string {
+	body := fakeSecret(alnum, crc32BodyLength)
+	return prefix + body + crc32Base62(body)
+}
+
+// exampleCharsets associe les noms utilisables dans les générateurs d'exemples à leur jeu de caractères
+var exampleCharsets = map[string]string{
+	"alnum":       alnum,
+	"alpha":       alnum[:52],
+	"lower_alnum": lowerAlnum,
+	"upper_alnum": upperAlnum,
+	"hex":         lowerHex,
+	"upper_hex":   upperHex,
+	"digits":      digits,
+	"base64url":   base64URL,
+	"base64":      base64Std,
+}
+
+// ExpandExample remplace les générateurs d'un exemple de règle par des valeurs
+// déterministes, afin qu'aucun secret ne soit écrit en clair dans les fichiers de règles:
+//
+//	{{alnum:36}}        36 caractères du jeu nommé (alnum, alpha, lower_alnum, upper_alnum, hex, upper_hex, digits, base64url, base64)
+//	{{checksum:ghp_}}   token GitHub/npm avec un checksum CRC32 valide
+//	{{jwt:{"role":"x"}}} JWT avec les claims JSON donnés
+//	{{block:LABEL}}     bloc ASCII armor "-----BEGIN LABEL-----" ... "-----END LABEL-----"
+//	{{uuid:1}}          UUID (l'argument varie la valeur)
+//	{{luhn:4539}}       numéro de carte de 16 chiffres valide selon Luhn
+//	{{iban:FR}}         IBAN dont la clé est valide
+//	{{nir:1}}           numéro de sécurité sociale français (sexe 1 ou 2) dont la clé est valide
+//
+// Les générateurs s'appliquent aux exemples des packs intégrés comme à ceux des règles
+// personnalisées. Un exemple contenant des accolades littérales (template Helm, Jinja)
+// les écrit "\{{": la séquence est remplacée par "{{" sans être interprétée.
+func ExpandExample(example string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.Index(example, "{{")
+		if start < 0 {
+			b.WriteString(example)
+			return b.String(), nil
+		}
+		if start > 0 && example[start-1] == '\\' {
+			b.WriteString(example[:start-1] + "{{")
+			example = example[start+2:]
+			continue
+		}
+		b.WriteString(example[:start])
+
+		// Fin du générateur: accolades équilibrées (les claims JWT sont un objet JSON)
+		end, depth := -1, 0
+		for i := start; i < len(example) && end < 0; i++ {
+			switch example[i] {
+			case '{':
+				depth++
+			case '}':
+				if depth--; depth == 0 {
+					end = i
+				}
+			}
+		}
+		if end < 0 {
+			return "", fmt.Errorf("générateur non fermé: %q (\\{{ pour des accolades littérales)", example[start:])
+		}
+
+		value, err := generateExample(example[start+2 : end-1])
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(value)
+		example = example[end+1:]
+	}
+}
+
+// generateExample produit la valeur d'un générateur "nom:argument"
+func generateExample(spec string) (string, error) {
+	name, arg, _ := strings.Cut(spec, ":")
+	if charset, ok := exampleCharsets[name]; ok {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n <= 0 {
+			return "", fmt.Errorf("générateur %q: longueur invalide %q", name, arg)
+		}
+		return fakeSecret(charset, n), nil
+	}
+
+	switch name {
+	case "checksum":
+		return fakeChecksumToken(arg), nil
+	case "jwt":
+		return fakeJWT(arg), nil
+	case "block":
+		return fakeBlock(arg), nil
+	case "uuid":
+		seed, err := strconv.Atoi(arg)
+		if err != nil || seed < 0 {
+			return "", fmt.Errorf("générateur uuid: argument invalide %q", arg)
+		}
+		return fakeUUID(seed), nil
+	case "luhn":
+		return fakeLuhn(arg), nil
+	case "iban":
+		return fakeIBAN(arg), nil
+	case "nir":
+		return fakeNIR(arg), nil
+	default:
+		return "", fmt.Errorf("générateur d'exemple inconnu %q (\\{{ pour des accolades littérales)", name)
+	}
+}
+
+// fakeLuhn génère un numéro de carte de 16 chiffres commençant par prefix et valide selon Luhn
+func fakeLuhn(prefix string) string {
+	body := prefix + fakeSecret(digits, 15-len(prefix))
+	for d := byte('0'); d <= '9'; d++ {
+		if validLuhn(body + string(d)) {
+			return body + string(d)
+		}
+	}
+	return body
+}
+
+// fakeIBAN génère un IBAN du pays donné dont la clé (modulo 97) est valide
+func fakeIBAN(country string) string {
+	bban := fakeSecret(digits, 23)
+	remainder := mod97(bban + ibanDigits(country) + "00")
+	return fmt.Sprintf("%s%02d%s", country, 98-remainder, bban)
+}
+
+// fakeNIR génère un numéro de sécurité sociale français dont la clé est valide
+func fakeNIR(sex string) string {
+	nir := sex + "850575" + fakeSecret(digits, 6)
+	return fmt.Sprintf("%s%02d", nir, 97-mod97(nir))
+}
@@ -0,0 +1,72 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestExpandExample(t *testing.T) {
+	tests := []struct {
+		example string
+		want    string
+	}{
+		{"sans générateur", "sans générateur"},
+		{"KEY={{digits:6}}", "KEY=" + fakeSecret(digits, 6)},
+		{"a={{hex:4}} b={{hex:4}}", "a=" + fakeSecret(lowerHex, 4) + " b=" + fakeSecret(lowerHex, 4)},
+		{`{{jwt:{"role":"anon"}}}`, fakeJWT(`{"role":"anon"}`)},
+		// Accolades littérales échappées
+		{`password: "\{{ .Values.db.password }}"`, `password: "{{ .Values.db.password }}"`},
+		{`\{{x}} puis {{digits:3}}`, "{{x}} puis " + fakeSecret(digits, 3)},
+	}
+	for _, tt := range tests {
+		got, err := ExpandExample(tt.example)
+		if err != nil {
+			t.Errorf("ExpandExample(%q): %v", tt.example, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandExample(%q) = %q, attendu %q", tt.example, got, tt.want)
+		}
+	}
+}
+
+func TestExpandExampleErrors(t *testing.T) {
+	tests := []struct {
+		example string
+		want    string
+	}{
+		{"{{ .Values.password }}", `générateur d'exemple inconnu " .Values.password " (\{{ pour des accolades littérales)`},
+		{"{{alnum:0}}", "longueur invalide"},
+		{"{{uuid:-1}}", "argument invalide"},
+		{"KEY={{alnum:12", "générateur non fermé"},
+	}
+	for _, tt := range tests {
+		_, err := ExpandExample(tt.example)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ExpandExample(%q): erreur %v, attendu %q", tt.example, err, tt.want)
+		}
+	}
+}
+
+// Une règle personnalisée peut donner un exemple contenant des accolades littérales
+func TestCustomRuleLiteralBraces(t *testing.T) {
+	rc := RuleConfig{
+		ID:               "helm-password",
+		Service:          "Helm",
+		Regex:            `password: "(?P<secret>[^"]{8,})"`,
+		Examples:         []string{`password: "{{alnum:16}}"`},
+		NegativeExamples: []string{`password: "\{{ .Values.password }}"`},
+	}
+	p, err := rc.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.NegativeExamples[0] != `password: "{{ .Values.password }}"` {
+		t.Errorf("exemple négatif %q", p.NegativeExamples[0])
+	}
+
+	rc.NegativeExamples = []string{`password: "{{ .Values.password }}"`}
+	if _, err := CompileRules([]RuleConfig{rc}); err == nil || !strings.Contains(err.Error(), `règle #1 (helm-password): negative_examples: générateur d'exemple inconnu`) {
+		t.Errorf("erreur %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package patterns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RuleFilter sélectionne les règles actives par identifiant et par tag
+type RuleFilter struct {
+	Rules        []string // Identifiants des règles à conserver (vide = toutes)
+	ExcludeRules []string // Identifiants des règles à écarter
+	Tags         []string // Tags dont une règle doit porter au moins un (vide = tous)
+}
+
+// IsZero indique si le filtre conserve toutes les règles
+func (f RuleFilter) IsZero() bool {
+	return len(f.Rules) == 0 && len(f.ExcludeRules) == 0 && len(f.Tags) == 0
+}
+
+// Validate vérifie que les identifiants du filtre désignent des règles de la liste
+func (f RuleFilter) Validate(list []Pattern) error {
+	known := make(map[string]bool, len(list))
+	for _, p := range list {
+		known[strings.ToLower(p.ID)] = true
+	}
+	for _, id := range append(append([]string(nil), f.Rules...), f.ExcludeRules...) {
+		if !known[strings.ToLower(strings.TrimSpace(id))] {
+			return fmt.Errorf("règle inconnue: %s", id)
+		}
+	}
+	return nil
+}
+
+// Apply retourne les règles de la liste retenues par le filtre
+func (f RuleFilter) Apply(list []Pattern) []Pattern {
+	if f.IsZero() {
+		return list
+	}
+	include := stringSet(f.Rules)
+	exclude := stringSet(f.ExcludeRules)
+	tags := stringSet(f.Tags)
+
+	filtered := make([]Pattern, 0, len(list))
+	for _, p := range list {
+		id := strings.ToLower(p.ID)
+		if (len(include) > 0 && !include[id]) || exclude[id] {
+			continue
+		}
+		if len(tags) > 0 && !hasTag(p, tags) {
+			continue
+		}
+		filtered = append(filtered, p)
+	}
+	return filtered
+}
+
+// hasTag indique si une règle porte l'un des tags donnés
+func hasTag(p Pattern, tags map[string]bool) bool {
+	for _, tag := range p.Tags {
+		if tags[strings.ToLower(tag)] {
+			return true
+		}
+	}
+	return false
+}
+
+// stringSet construit un ensemble de valeurs normalisées (espaces retirés, minuscules)
+func stringSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			set[strings.ToLower(v)] = true
+		}
+	}
+	return set
+}
@@ -0,0 +1,90 @@
+package patterns
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func filterPatterns() []Pattern {
+	re := regexp.MustCompile("x")
+	return []Pattern{
+		{ID: "aws-access-key-id", Regex: re, Tags: []string{"cloud", "aws"}},
+		{ID: "github-pat", Regex: re, Tags: []string{"vcs"}},
+		{ID: "slack-bot-token", Regex: re, Tags: []string{"saas", "chat"}},
+		{ID: "generic-credential", Regex: re},
+	}
+}
+
+func patternIDs(list []Pattern) string {
+	ids := make([]string, 0, len(list))
+	for _, p := range list {
+		ids = append(ids, p.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestRuleFilterApply(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter RuleFilter
+		want   string
+	}{
+		{"aucun filtre", RuleFilter{}, "aws-access-key-id,github-pat,slack-bot-token,generic-credential"},
+		{"inclusion", RuleFilter{Rules: []string{"github-pat", " Slack-Bot-Token "}}, "github-pat,slack-bot-token"},
+		{"exclusion", RuleFilter{ExcludeRules: []string{"generic-credential"}}, "aws-access-key-id,github-pat,slack-bot-token"},
+		{"inclusion et exclusion", RuleFilter{Rules: []string{"github-pat", "slack-bot-token"}, ExcludeRules: []string{"github-pat"}}, "slack-bot-token"},
+		{"tags", RuleFilter{Tags: []string{"VCS", "chat"}}, "github-pat,slack-bot-token"},
+		{"tags et exclusion", RuleFilter{Tags: []string{"cloud", "vcs"}, ExcludeRules: []string{"aws-access-key-id"}}, "github-pat"},
+		{"tag inconnu", RuleFilter{Tags: []string{"payments"}}, ""},
+	}
+	for _, tt := range tests {
+		if got := patternIDs(tt.filter.Apply(filterPatterns())); got != tt.want {
+			t.Errorf("%s: %s, attendu %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+// Un identifiant inconnu est refusé, qu'il soit inclus ou exclu
+func TestRuleFilterValidate(t *testing.T) {
+	tests := []struct {
+		filter RuleFilter
+		want   string
+	}{
+		{RuleFilter{Rules: []string{"GitHub-PAT"}, Tags: []string{"inconnu"}}, ""},
+		{RuleFilter{Rules: []string{"github-pat", "gitlab-pat"}}, "règle inconnue: gitlab-pat"},
+		{RuleFilter{ExcludeRules: []string{"aws-secret"}}, "règle inconnue: aws-secret"},
+	}
+	for _, tt := range tests {
+		err := tt.filter.Validate(filterPatterns())
+		if (tt.want == "" && err != nil) || (tt.want != "" && (err == nil || err.Error() != tt.want)) {
+			t.Errorf("%+v: erreur %v, attendu %q", tt.filter, err, tt.want)
+		}
+	}
+}
+
+func TestSeverityAtLeast(t *testing.T) {
+	tests := []struct {
+		severity Severity
+		minimum  Severity
+		want     bool
+	}{
+		{SeverityCritical, SeverityHigh, true},
+		{SeverityHigh, SeverityHigh, true},
+		{SeverityMedium, SeverityHigh, false},
+		{SeverityInfo, SeverityLow, false},
+		{SeverityLow, SeverityInfo, true},
+	}
+	for _, tt := range tests {
+		if got := tt.severity.AtLeast(tt.minimum); got != tt.want {
+			t.Errorf("%s.AtLeast(%s) = %v, attendu %v", tt.severity, tt.minimum, got, tt.want)
+		}
+	}
+
+	if s, err := ParseSeverity(" HIGH "); err != nil || s != SeverityHigh {
+		t.Errorf("ParseSeverity(\" HIGH \") = %q, %v", s, err)
+	}
+	if _, err := ParseSeverity("urgent"); err == nil {
+		t.Error("sévérité urgent acceptée")
+	}
+}
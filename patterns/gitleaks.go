@@ -0,0 +1,168 @@
+package patterns
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// FormatGitleaks désigne le format de configuration de gitleaks (.gitleaks.toml)
+const FormatGitleaks = "gitleaks"
+
+// gitleaksConfig reflète un fichier .gitleaks.toml (gitleaks v8)
+type gitleaksConfig struct {
+	Title  string `toml:"title"`
+	Extend struct {
+		UseDefault bool   `toml:"useDefault"`
+		Path       string `toml:"path"`
+	} `toml:"extend"`
+	Rules      []gitleaksRule      `toml:"rules"`
+	Allowlist  *gitleaksAllowlist  `toml:"allowlist"`  // Format historique
+	Allowlists []gitleaksAllowlist `toml:"allowlists"` // Format >= v8.21
+}
+
+// gitleaksRule reflète une règle gitleaks
+type gitleaksRule struct {
+	ID          string              `toml:"id"`
+	Description string              `toml:"description"`
+	Regex       string              `toml:"regex"`
+	SecretGroup int                 `toml:"secretGroup"`
+	Entropy     float64             `toml:"entropy"`
+	Keywords    []string            `toml:"keywords"`
+	Path        string              `toml:"path"`
+	Tags        []string            `toml:"tags"`
+	Allowlist   *gitleaksAllowlist  `toml:"allowlist"`
+	Allowlists  []gitleaksAllowlist `toml:"allowlists"`
+}
+
+// gitleaksAllowlist reflète une allowlist gitleaks (globale ou par règle)
+type gitleaksAllowlist struct {
+	Description string   `toml:"description"`
+	Condition   string   `toml:"condition"`
+	RegexTarget string   `toml:"regexTarget"`
+	Regexes     []string `toml:"regexes"`
+	Paths       []string `toml:"paths"`
+	Stopwords   []string `toml:"stopwords"`
+	Commits     []string `toml:"commits"`
+}
+
+// ImportGitleaks convertit une configuration gitleaks en fichier de règles goleaks.
+// Les fonctionnalités sans équivalent (commits, condition AND, regexTarget "line",
+// [extend]) sont signalées dans les avertissements retournés.
+func ImportGitleaks(data []byte) (*RuleFile, []string, error) {
+	var gc gitleaksConfig
+	if _, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&gc); err != nil {
+		return nil, nil, err
+	}
+
+	var warnings []string
+	if gc.Extend.UseDefault || gc.Extend.Path != "" {
+		warnings = append(warnings, "[extend] ignoré: les règles par défaut de gitleaks ne sont pas importées (les patterns intégrés restent actifs en mode merge)")
+	}
+
+	// Allowlist globale, ajoutée à chaque règle
+	var global AllowlistConfig
+	if gc.Allowlist != nil {
+		global = mergeAllowlistConfig(global, convertGitleaksAllowlist(*gc.Allowlist, "allowlist globale", &warnings))
+	}
+	for i, al := range gc.Allowlists {
+		global = mergeAllowlistConfig(global, convertGitleaksAllowlist(al, fmt.Sprintf("allowlist globale #%d", i+1), &warnings))
+	}
+
+	rf := &RuleFile{Rules: make([]RuleConfig, 0, len(gc.Rules))}
+	for i, gr := range gc.Rules {
+		label := fmt.Sprintf("règle #%d (%s)", i+1, gr.ID)
+		if gr.Regex == "" {
+			warnings = append(warnings, fmt.Sprintf("%s ignorée: règle sans regex (détection par chemin seul non supportée)", label))
+			continue
+		}
+
+		rc := RuleConfig{
+			ID:          gr.ID,
+			Service:     serviceFromID(gr.ID),
+			Description: gr.Description,
+			Tags:        gr.Tags,
+			Keywords:    gr.Keywords,
+			Regex:       gr.Regex,
+			SecretGroup: gitleaksSecretGroup(gr),
+			Entropy:     gr.Entropy,
+			PathRegex:   gr.Path,
+			Severity:    string(SeverityHigh),
+			Allowlist:   global,
+		}
+		if gr.Allowlist != nil {
+			rc.Allowlist = mergeAllowlistConfig(rc.Allowlist, convertGitleaksAllowlist(*gr.Allowlist, label, &warnings))
+		}
+		for _, al := range gr.Allowlists {
+			rc.Allowlist = mergeAllowlistConfig(rc.Allowlist, convertGitleaksAllowlist(al, label, &warnings))
+		}
+
+		if _, err := rc.Compile(); err != nil {
+			return nil, warnings, fmt.Errorf("%s: %v", label, err)
+		}
+		rf.Rules = append(rf.Rules, rc)
+	}
+
+	return rf, warnings, nil
+}
+
+// gitleaksSecretGroup retourne le groupe de capture du secret d'une règle gitleaks.
+// Sans secretGroup, gitleaks retient le premier groupe capturé plutôt que la
+// correspondance complète: le groupe 1 est utilisé si la regex en a un (sauf groupe
+// nommé "secret", déjà reconnu par goleaks).
+func gitleaksSecretGroup(gr gitleaksRule) int {
+	if gr.SecretGroup != 0 {
+		return gr.SecretGroup
+	}
+	re, err := regexp.Compile(gr.Regex)
+	if err != nil || re.NumSubexp() == 0 || re.SubexpIndex(SecretGroup) >= 0 {
+		return 0 // Regex invalide: l'erreur est signalée par Compile
+	}
+	return 1
+}
+
+// convertGitleaksAllowlist convertit une allowlist gitleaks (les chemins gitleaks sont des regex)
+func convertGitleaksAllowlist(al gitleaksAllowlist, label string, warnings *[]string) AllowlistConfig {
+	converted := AllowlistConfig{
+		Stopwords:   al.Stopwords,
+		PathRegexes: al.Paths,
+	}
+
+	switch strings.ToLower(al.RegexTarget) {
+	case "", "secret", "match":
+		converted.Regexes = al.Regexes
+	default:
+		if len(al.Regexes) > 0 {
+			*warnings = append(*warnings, fmt.Sprintf("%s: regexTarget %q non supporté, %d regex ignorée(s)", label, al.RegexTarget, len(al.Regexes)))
+		}
+	}
+	if strings.EqualFold(al.Condition, "AND") {
+		*warnings = append(*warnings, fmt.Sprintf("%s: condition AND traitée comme OR", label))
+	}
+	if len(al.Commits) > 0 {
+		*warnings = append(*warnings, fmt.Sprintf("%s: %d commit(s) ignoré(s) (allowlist par commit non supportée)", label, len(al.Commits)))
+	}
+	return converted
+}
+
+// mergeAllowlistConfig concatène deux allowlists
+func mergeAllowlistConfig(a, b AllowlistConfig) AllowlistConfig {
+	return AllowlistConfig{
+		Regexes:     append(append([]string(nil), a.Regexes...), b.Regexes...),
+		Stopwords:   append(append([]string(nil), a.Stopwords...), b.Stopwords...),
+		Paths:       append(append([]string(nil), a.Paths...), b.Paths...),
+		PathRegexes: append(append([]string(nil), a.PathRegexes...), b.PathRegexes...),
+	}
+}
+
+// serviceFromID construit un nom de service lisible à partir d'un identifiant ("acme-api-key" -> "Acme Api Key")
+func serviceFromID(id string) string {
+	words := strings.FieldsFunc(id, func(r rune) bool { return r == '-' || r == '_' || r == '.' })
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,68 @@
+package patterns
+
+// SecretGroup est le nom du groupe de capture contenant le secret dans une regex contextuelle
+const SecretGroup = "secret"
+
+// Match représente une correspondance d'un pattern dans une ligne
+type Match struct {
+	Secret string // Valeur du secret (groupe "secret" s'il existe, sinon la correspondance complète)
+	Start  int    // Position de début du secret dans la ligne
+	End    int    // Position de fin du secret dans la ligne
+
+	Full      string // Correspondance complète de la regex, contexte compris
+	FullStart int    // Position de début de la correspondance complète dans la ligne
+}
+
+// FindAll retourne toutes les correspondances du pattern dans une ligne.
+// Si la regex déclare un groupe nommé "secret" (ou si SecretGroup est défini),
+// seul ce groupe est retenu : le reste de l'expression sert uniquement de
+// contexte (ex: nom de variable).
+func (p Pattern) FindAll(line string) []Match {
+	group := p.SecretGroup
+	if group == 0 {
+		group = p.Regex.SubexpIndex(SecretGroup)
+	}
+
+	var matches []Match
+	for _, loc := range p.Regex.FindAllStringSubmatchIndex(line, -1) {
+		start, end := loc[0], loc[1]
+		if group > 0 {
+			if 2*group+1 >= len(loc) || loc[2*group] < 0 {
+				continue // Groupe inexistant ou optionnel non capturé
+			}
+			start, end = loc[2*group], loc[2*group+1]
+		}
+		matches = append(matches, Match{
+			Secret:    line[start:end],
+			Start:     start,
+			End:       end,
+			Full:      line[loc[0]:loc[1]],
+			FullStart: loc[0],
+		})
+	}
+	return matches
+}
+
+// FindSecrets retourne les correspondances retenues comme secrets: celles dont
+// l'entropie atteint les minimums de la règle, qui ne sont pas des valeurs
+// d'exemple (si la règle les rejette) et qui ne sont pas dans son allowlist
+func (p Pattern) FindSecrets(line string) []Match {
+	matches := p.FindAll(line)
+	kept := matches[:0]
+	for _, m := range matches {
+		if p.Entropy > 0 && ShannonEntropy(m.Secret) < p.Entropy {
+			continue
+		}
+		if !p.EntropyThresholds.Allows(m.Secret) {
+			continue
+		}
+		if p.RejectPlaceholders && IsPlaceholder(m.Secret) {
+			continue
+		}
+		if p.Allowlist.AllowsValue(m.Secret) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	return kept
+}
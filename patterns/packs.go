@@ -0,0 +1,194 @@
+package patterns
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// RulesetVersion est la version de l'ensemble des packs intégrés, incrémentée à
+// chaque modification d'un pack et rapportée dans les sorties JSON et SARIF
+const RulesetVersion = "2026.10.5"
+
+// Packs de règles intégrés
+const (
+	PackCore     = "core"
+	PackCloud    = "cloud"
+	PackAI       = "ai"
+	PackPayments = "payments"
+	PackSaaS     = "saas"
+	PackPII      = "pii"
+)
+
+// PackNames liste les packs intégrés dans leur ordre de chargement
+var PackNames = []string{PackCore, PackCloud, PackAI, PackPayments, PackSaaS, PackPII}
+
+// DefaultPacks liste les packs activés par défaut (les données personnelles sont en option)
+var DefaultPacks = []string{PackCore, PackCloud, PackAI, PackPayments, PackSaaS}
+
+// packFiles contient un fichier par pack (rules/<nom>.toml). Les exemples des règles
+// utilisent des générateurs ({{alnum:36}}, {{checksum:ghp_}}...) afin qu'aucun secret
+// ne soit écrit en clair: voir ExpandExample.
+//
+//go:embed rules/*.toml
+var packFiles embed.FS
+
+// Pack est un ensemble versionné de règles intégrées
+type Pack struct {
+	Name        string
+	Version     string
+	Description string
+	Patterns    []Pattern
+}
+
+// packFile représente un fichier de pack (rules/<nom>.toml)
+type packFile struct {
+	Name        string       `toml:"name"`
+	Version     string       `toml:"version"`
+	Description string       `toml:"description"`
+	Rules       []RuleConfig `toml:"rules"`
+}
+
+// packs contient les packs intégrés, chargés au démarrage
+var packs []Pack
+
+// packsErr est l'erreur de chargement des packs intégrés (voir PacksError)
+var packsErr error
+
+func init() {
+	var errs []error
+	for _, name := range PackNames {
+		pack, err := loadPack(name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pack de règles intégré %q: %v", name, err))
+			continue
+		}
+		packs = append(packs, pack)
+	}
+	packsErr = errors.Join(errs...)
+
+	for _, p := range packs {
+		for _, name := range DefaultPacks {
+			if p.Name == name {
+				Patterns = append(Patterns, p.Patterns...)
+			}
+		}
+	}
+}
+
+// PacksError retourne l'erreur de chargement des packs intégrés, nil s'ils sont
+// tous valides. Un pack invalide est absent de GetPacks et des patterns par défaut.
+func PacksError() error {
+	return packsErr
+}
+
+// loadPack lit et compile un pack intégré
+func loadPack(name string) (Pack, error) {
+	data, err := packFiles.ReadFile("rules/" + name + ".toml")
+	if err != nil {
+		return Pack{}, err
+	}
+	return parsePack(name, data)
+}
+
+// parsePack compile le contenu d'un fichier de pack
+func parsePack(name string, data []byte) (Pack, error) {
+	var pf packFile
+	if _, err := toml.NewDecoder(bytes.NewReader(data)).Decode(&pf); err != nil {
+		return Pack{}, err
+	}
+	if pf.Name != name {
+		return Pack{}, fmt.Errorf("nom déclaré %q différent du fichier", pf.Name)
+	}
+	if pf.Version == "" {
+		return Pack{}, fmt.Errorf("version manquante")
+	}
+
+	list, err := CompileRules(pf.Rules)
+	if err != nil {
+		return Pack{}, err
+	}
+	for i := range list {
+		list[i].Pack = name
+	}
+
+	return Pack{
+		Name:        pf.Name,
+		Version:     pf.Version,
+		Description: pf.Description,
+		Patterns:    list,
+	}, nil
+}
+
+// GetPacks retourne les packs intégrés
+func GetPacks() []Pack {
+	return packs
+}
+
+// FindPack retourne le pack intégré de ce nom
+func FindPack(name string) (Pack, bool) {
+	for _, p := range packs {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return Pack{}, false
+}
+
+// PackPatterns retourne les patterns des packs nommés, dans l'ordre de chargement des packs
+func PackPatterns(names []string) ([]Pattern, error) {
+	if packsErr != nil {
+		return nil, packsErr
+	}
+	enabled := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := FindPack(name); !ok {
+			return nil, fmt.Errorf("pack de règles inconnu %q (attendu: %s)", name, strings.Join(PackNames, ", "))
+		}
+		enabled[name] = true
+	}
+
+	var list []Pattern
+	for _, p := range packs {
+		if enabled[p.Name] {
+			list = append(list, p.Patterns...)
+		}
+	}
+	return list, nil
+}
+
+// PackVersion identifie un pack et sa version
+type PackVersion struct {
+	Name    string
+	Version string
+}
+
+// Ruleset décrit le jeu de règles utilisé par un scan
+type Ruleset struct {
+	Version string        // Version de l'ensemble des packs intégrés (RulesetVersion)
+	Packs   []PackVersion // Packs dont au moins une règle est active
+	Custom  int           // Nombre de règles personnalisées (hors packs)
+}
+
+// RulesetOf décrit le jeu de règles d'une liste de patterns
+func RulesetOf(list []Pattern) Ruleset {
+	used := make(map[string]bool)
+	rs := Ruleset{Version: RulesetVersion}
+	for _, p := range list {
+		if p.Pack == "" {
+			rs.Custom++
+		}
+		used[p.Pack] = true
+	}
+	for _, p := range packs {
+		if used[p.Name] {
+			rs.Packs = append(rs.Packs, PackVersion{Name: p.Name, Version: p.Version})
+		}
+	}
+	return rs
+}
@@ -0,0 +1,87 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+)
+
+// Chaque pack intégré se charge et se compile
+func TestEmbeddedPacks(t *testing.T) {
+	if err := PacksError(); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make(map[string]string)
+	for _, name := range PackNames {
+		pack, err := loadPack(name)
+		if err != nil {
+			t.Errorf("pack %s: %v", name, err)
+			continue
+		}
+		if pack.Description == "" || len(pack.Patterns) == 0 {
+			t.Errorf("pack %s: description ou règles manquantes", name)
+		}
+		for _, p := range pack.Patterns {
+			if other, ok := ids[p.ID]; ok {
+				t.Errorf("règle %s déclarée dans les packs %s et %s", p.ID, other, name)
+			}
+			ids[p.ID] = name
+			if p.Pack != name {
+				t.Errorf("règle %s: pack %q, attendu %q", p.ID, p.Pack, name)
+			}
+			// Un tag portant le nom d'un pack désigne le pack de la règle: --tags et
+			// --packs sélectionnent alors les mêmes règles
+			for _, tag := range p.Tags {
+				if tag != name && isPackName(tag) {
+					t.Errorf("règle %s du pack %s: tag %q", p.ID, name, tag)
+				}
+			}
+		}
+	}
+
+	// Tous les fichiers embarqués correspondent à un pack déclaré
+	files, err := packFiles.ReadDir("rules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != len(PackNames) {
+		t.Errorf("%d fichiers de packs pour %d packs déclarés", len(files), len(PackNames))
+	}
+
+	defaults, err := PackPatterns(DefaultPacks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(defaults) != len(GetPatterns()) {
+		t.Errorf("%d patterns par défaut, attendu %d", len(GetPatterns()), len(defaults))
+	}
+}
+
+// Un pack invalide est signalé par une erreur
+func TestParsePackErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"toml", "name = 'core'\nversion = ", "toml"},
+		{"nom", "name = 'cloud'\nversion = '1.0.0'", "différent du fichier"},
+		{"version", "name = 'core'", "version manquante"},
+		{"regex", "name = 'core'\nversion = '1.0.0'\n[[rules]]\nservice = 'X'\nregex = '('", "regex invalide"},
+	}
+	for _, tt := range tests {
+		_, err := parsePack("core", []byte(tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: erreur %v, attendu %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func isPackName(name string) bool {
+	for _, pack := range PackNames {
+		if pack == name {
+			return true
+		}
+	}
+	return false
+}
@@ -2,148 +2,112 @@ package patterns
 
 import (
 	"regexp"
+	"strings"
 )
 
+// DefaultCWE est la référence CWE des identifiants codés en dur
+const DefaultCWE = "CWE-798"
+
+// AnalyzerJWT décode les JWT trouvés (en-tête et claims) et ajuste la sévérité selon le rôle et l'expiration
+const AnalyzerJWT = "jwt"
+
+// AnalyzerURL analyse une URL contenant un identifiant (mot de passe ou token en paramètre):
+// elle doit être valide pour net/url, et seul l'identifiant est masqué à l'affichage
+const AnalyzerURL = "url"
+
+// Analyzers liste les analyses complémentaires disponibles pour une règle (champ Analyzer)
+var Analyzers = []string{AnalyzerJWT, AnalyzerURL}
+
+// IsKnownAnalyzer indique si un nom d'analyseur est reconnu
+func IsKnownAnalyzer(name string) bool {
+	for _, a := range Analyzers {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultVerifyHeader est l'en-tête d'authentification envoyé par verify-light
+const DefaultVerifyHeader = "Authorization: Bearer {secret}"
+
 // Pattern représente un pattern de détection de secret
 type Pattern struct {
-	Service    string
-	Regex      *regexp.Regexp
-	Risk       string // "high", "medium", "low"
-	IsHighRisk bool   // true pour verify-light (secrets dangereux prioritaires)
+	ID                 string // Identifiant stable (ex: "aws-access-key-id")
+	Pack               string // Pack intégré d'origine (ex: "cloud"), vide pour une règle personnalisée
+	Service            string
+	Description        string
+	Tags               []string // Catégories: cloud, ai, payments, vcs, saas...
+	CWE                string   // Référence CWE (ex: "CWE-798")
+	DocsURL            string   // Documentation de remédiation / rotation
+	Keywords           []string // Littéraux dont la présence conditionne l'évaluation de la regex (vide = toujours évaluée)
+	Regex              *regexp.Regexp
+	SecretGroup        int               // Index du groupe de capture contenant le secret (0 = groupe nommé "secret" ou correspondance complète)
+	Entropy            float64           // Entropie de Shannon minimale du secret (0 = pas de contrôle)
+	EntropyThresholds  EntropyThresholds // Entropie normalisée minimale par jeu de caractères (zéro = seuils par défaut en mode smart)
+	RejectPlaceholders bool              // Ignorer les valeurs d'exemple et les références (voir IsPlaceholder)
+	Fallback           bool              // Règle générique: ignorée là où une règle spécifique a déjà trouvé un secret
+	PathRegex          *regexp.Regexp    // Regex des chemins (relatifs à la racine du scan) où la règle s'applique (en plus de Paths)
+	EndRegex           *regexp.Regexp    // Si défini, Regex marque le début d'un bloc multi-lignes et EndRegex sa fin
+	Companions         []Companion       // Secrets compagnons recherchés autour de la correspondance (règle composite)
+	Paths              []string          // Globs des fichiers où la règle s'applique (vide = partout)
+	FileTypes          []string          // Extensions ou noms de fichiers où la règle s'applique (vide = tous)
+	Allowlist          Allowlist         // Valeurs et chemins connus comme sûrs
+	Analyzer           string            // Analyse complémentaire des secrets trouvés (ex: "jwt"), voir Analyzers
+	Validator          string            // Validateur de checksum hors ligne (ex: "github"), voir Validators
+	Severity           Severity          // Sévérité par défaut des secrets (critical à info)
+	Confidence         Confidence        // Confiance par défaut dans les correspondances (high, medium, low)
+	Risk               string            // Deprecated: utiliser Severity, dont Risk reprend la valeur
+	IsHighRisk         bool              // true pour verify-light (secrets dangereux prioritaires)
+	VerifyURL          string            // Endpoint interrogé par verify-light (vide = pas de vérification)
+	VerifyHeader       string            // En-tête d'authentification, "{secret}" remplacé par le secret (défaut: "Authorization: Bearer {secret}")
+
+	Examples         []string // Textes que la règle doit détecter (goleaks rules test)
+	NegativeExamples []string // Textes que la règle ne doit pas détecter
 }
 
-// Patterns contient tous les patterns de détection optimisés
+// Companion décrit un secret compagnon d'une règle composite (ex: la secret access key
+// qui accompagne un identifiant de clé AWS). Il est rapporté avec le secret principal.
+type Companion struct {
+	Name        string         // Nom affiché (ex: "secret access key")
+	Keywords    []string       // Préfiltre, comme pour Pattern
+	Regex       *regexp.Regexp // Peut déclarer un groupe nommé "secret"
+	Required    bool           // Le secret principal n'est rapporté que si le compagnon est trouvé
+	WithinLines int            // Distance maximale en lignes (0 = n'importe où dans le fichier)
+}
+
+// Patterns contient les patterns des packs activés par défaut (voir DefaultPacks)
 var Patterns []Pattern
 
-func init() {
-	// Compilation des regex au démarrage pour optimiser les performances
-	Patterns = []Pattern{
-		{
-			Service:    "OpenAI",
-			Regex:      regexp.MustCompile(`\bsk-[a-zA-Z0-9]{48}\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "Grok xAI",
-			Regex:      regexp.MustCompile(`\bsk-grok-[a-zA-Z0-9_\-]{93}AA\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "Anthropic",
-			Regex:      regexp.MustCompile(`\bsk-ant-api03-[a-zA-Z0-9_\-]{93}AA\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "AWS Access Key",
-			Regex:      regexp.MustCompile(`\b(AKIA|ASIA|ABIA|ACCA)[A-Z0-9]{16}\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "GitHub PAT",
-			Regex:      regexp.MustCompile(`\bghp_[a-zA-Z0-9]{36}\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "Vercel",
-			Regex:      regexp.MustCompile(`\bvercel_[a-zA-Z0-9]{32}\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Supabase",
-			Regex:      regexp.MustCompile(`\beyJ[a-zA-Z0-9._-]{100,}\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Fly.io",
-			Regex:      regexp.MustCompile(`\bflyv1_[a-zA-Z0-9]{40}\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Stripe",
-			Regex:      regexp.MustCompile(`\bsk_live_[a-zA-Z0-9]{24}\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "Slack Bot",
-			Regex:      regexp.MustCompile(`\bxoxb-[0-9]{11}-[0-9]{12}-[a-zA-Z0-9]{24}\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Discord Bot",
-			Regex:      regexp.MustCompile(`\b[a-zA-Z0-9]{24}\.[a-zA-Z0-9]{6}\.[a-zA-Z0-9_\-]{27}\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Adobe",
-			Regex:      regexp.MustCompile(`\bp8e-[a-z0-9]{32}\b`),
-			Risk:       "medium",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Airtable PAT",
-			Regex:      regexp.MustCompile(`\bpat[a-zA-Z0-9]{14}\.[a-f0-9]{64}\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Algolia",
-			Regex:      regexp.MustCompile(`\b[a-z0-9]{32}\b`),
-			Risk:       "medium", // Nécessite contexte pour éviter faux positifs
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Alibaba",
-			Regex:      regexp.MustCompile(`\bLTAI[a-z0-9]{20}\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "Asana",
-			Regex:      regexp.MustCompile(`\b[a-z0-9]{32}\b`),
-			Risk:       "medium", // Nécessite contexte pour éviter faux positifs
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Cloudflare",
-			Regex:      regexp.MustCompile(`\b[a-z0-9_-]{40}\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
-		{
-			Service:    "Bitbucket",
-			Regex:      regexp.MustCompile(`\b[a-z0-9=_\-]{64}\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Atlassian",
-			Regex:      regexp.MustCompile(`\b(ATATT3[A-Za-z0-9_\-=]{186})\b`),
-			Risk:       "high",
-			IsHighRisk: false,
-		},
-		{
-			Service:    "Azure AD",
-			Regex:      regexp.MustCompile(`\b[a-zA-Z0-9_~.]{3}\dQ~[a-zA-Z0-9_~.-]{31,34}\b`),
-			Risk:       "high",
-			IsHighRisk: true,
-		},
+// GetPatterns retourne les patterns des packs activés par défaut
+func GetPatterns() []Pattern {
+	return Patterns
+}
+
+// RuleID génère un identifiant stable à partir d'un nom de service ("AWS Access Key" -> "aws-access-key")
+func RuleID(service string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(service) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
 	}
+	return strings.TrimSuffix(b.String(), "-")
 }
 
-// GetPatterns retourne tous les patterns compilés
-func GetPatterns() []Pattern {
-	return Patterns
+// FindByID retourne le pattern correspondant à un identifiant de règle
+func FindByID(list []Pattern, id string) (Pattern, bool) {
+	for _, p := range list {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Pattern{}, false
 }
 
 // PatternCount retourne le nombre total de patterns
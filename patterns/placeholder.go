@@ -0,0 +1,55 @@
+package patterns
+
+import (
+	"regexp"
+	"strings"
+)
+
+// placeholderValues liste les valeurs d'exemple fréquentes (comparaison exacte, insensible à la casse)
+var placeholderValues = map[string]bool{
+	"password": true, "passwd": true, "secret": true, "token": true, "apikey": true, "api_key": true,
+	"null": true, "none": true, "nil": true, "undefined": true, "true": true, "false": true,
+	"empty": true, "default": true, "test": true, "testing": true, "string": true, "required": true,
+}
+
+// placeholderMarkers liste les fragments qui trahissent une valeur d'exemple ou une référence
+var placeholderMarkers = []string{
+	"changeme", "change_me", "change-me", "example", "placeholder", "your_", "your-", "yourpass",
+	"dummy", "redacted", "replace", "insert", "fixme", "todo", "xxxx", "****", "...",
+	"${", "{{", "}}", "%s", "%(", "<", ">", "(", "[", "process.env", "os.environ", "getenv",
+}
+
+// memberAccess reconnaît une référence à un champ ou une variable (ex: config.password, this.apiKey)
+var memberAccess = regexp.MustCompile(`^&?[A-Za-z_$][\w$]*(\.[A-Za-z_$][\w$]*)+[)\]}]*$`)
+
+// IsPlaceholder indique si une valeur affectée à un identifiant sensible est une
+// valeur d'exemple, une référence (variable d'environnement, template, appel de
+// fonction) ou une valeur triviale plutôt qu'un vrai secret
+func IsPlaceholder(value string) bool {
+	lower := strings.ToLower(value)
+	if placeholderValues[lower] {
+		return true
+	}
+	for _, marker := range placeholderMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+
+	// Variable shell ou référence à un autre champ
+	if strings.HasPrefix(value, "$") || memberAccess.MatchString(value) {
+		return true
+	}
+
+	// Valeur numérique ou répétition d'un seul caractère
+	digits, repeated := true, true
+	for i := 0; i < len(value); i++ {
+		if value[i] < '0' || value[i] > '9' {
+			digits = false
+		}
+		if value[i] != value[0] {
+			repeated = false
+		}
+	}
+	return digits || repeated
+}
@@ -0,0 +1,41 @@
+package patterns
+
+import "testing"
+
+func TestIsPlaceholder(t *testing.T) {
+	tests := []struct {
+		value string
+		want  bool
+	}{
+		{"xxxxxxxxxxxx", true},
+		{"XXXX-XXXX-XXXX", true},
+		{"changeme", true},
+		{"ChangeMe123!", true},
+		{"<your-key>", true},
+		{"your_api_key_here", true},
+		{"${VAR}", true},
+		{"${DB_PASSWORD:-secret}", true},
+		{"{{ .Values.password }}", true},
+		{"$DB_PASS", true},
+		{"os.Getenv(\"TOKEN\")", true},
+		{"process.env.API_KEY", true},
+		{"config.password", true},
+		{"this.apiKey", true},
+		{"settings.API_KEY)", true},
+		{"Password", true},
+		{"undefined", true},
+		{"12345678", true},
+		{"aaaaaaaaaaaa", true},
+		{"sk-REDACTED", true},
+		{"********", true},
+		{"hunter2-prod!", false},
+		{"Wq9#Lm2!Zx7pRt", false},
+		{"9fK2mP8qLx3Rz7Tw", false},
+		{"v1.2.3-beta", false},
+	}
+	for _, tt := range tests {
+		if got := IsPlaceholder(tt.value); got != tt.want {
+			t.Errorf("IsPlaceholder(%q) = %v, attendu %v", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package patterns
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// Allowlist décrit les valeurs et chemins connus comme sûrs pour une règle
+type Allowlist struct {
+	Regexes   []*regexp.Regexp // Valeurs sûres: un secret correspondant est ignoré
+	Stopwords []string         // Un secret contenant l'un de ces mots est ignoré (insensible à la casse)
+	Paths     []string         // Globs des fichiers où la règle est ignorée
+	PathRegex []*regexp.Regexp // Regex des chemins (relatifs à la racine du scan) où la règle est ignorée
+}
+
+// IsEmpty indique si l'allowlist ne contient aucune entrée
+func (a Allowlist) IsEmpty() bool {
+	return len(a.Regexes) == 0 && len(a.Stopwords) == 0 && len(a.Paths) == 0 && len(a.PathRegex) == 0
+}
+
+// Merge ajoute les entrées d'une autre allowlist
+func (a Allowlist) Merge(other Allowlist) Allowlist {
+	return Allowlist{
+		Regexes:   append(append([]*regexp.Regexp(nil), a.Regexes...), other.Regexes...),
+		Stopwords: append(append([]string(nil), a.Stopwords...), other.Stopwords...),
+		Paths:     append(append([]string(nil), a.Paths...), other.Paths...),
+		PathRegex: append(append([]*regexp.Regexp(nil), a.PathRegex...), other.PathRegex...),
+	}
+}
+
+// AllowsValue indique si un secret est explicitement autorisé par l'allowlist
+func (a Allowlist) AllowsValue(secret string) bool {
+	for _, re := range a.Regexes {
+		if re.MatchString(secret) {
+			return true
+		}
+	}
+	if len(a.Stopwords) > 0 {
+		lower := strings.ToLower(secret)
+		for _, word := range a.Stopwords {
+			if word != "" && strings.Contains(lower, strings.ToLower(word)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AllowsPath indique si un fichier est exclu par l'allowlist. filePath est relatif
+// à la racine du scan pour que les regex ancrées (ex: "^config/") s'appliquent.
+func (a Allowlist) AllowsPath(filePath string) bool {
+	for _, glob := range a.Paths {
+		if MatchGlob(glob, filePath) {
+			return true
+		}
+	}
+	for _, re := range a.PathRegex {
+		if re.MatchString(filepath.ToSlash(filePath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// AppliesTo indique si la règle doit être évaluée sur un fichier, selon ses
+// chemins, ses types de fichiers et les chemins de son allowlist. filePath est
+// relatif à la racine du scan (voir AllowsPath).
+func (p Pattern) AppliesTo(filePath string) bool {
+	if len(p.FileTypes) > 0 {
+		ext := strings.ToLower(filepath.Ext(filePath))
+		base := strings.ToLower(filepath.Base(filePath))
+		found := false
+		for _, ft := range p.FileTypes {
+			ft = strings.ToLower(ft)
+			if ft == ext || ft == base {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(p.Paths) > 0 {
+		found := false
+		for _, glob := range p.Paths {
+			if MatchGlob(glob, filePath) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if p.PathRegex != nil && !p.PathRegex.MatchString(filepath.ToSlash(filePath)) {
+		return false
+	}
+
+	return !p.Allowlist.AllowsPath(filePath)
+}
+
+// globCache mémorise les globs convertis en regex
+var globCache sync.Map
+
+// MatchGlob teste un chemin contre un glob. Un glob sans "/" est comparé au nom
+// du fichier (ex: "*.lock", "go.sum"). Un glob avec "/" est comparé à la fin du
+// chemin et supporte "**" pour un nombre quelconque de dossiers (ex: "config/**/*.yaml").
+func MatchGlob(glob string, filePath string) bool {
+	filePath = filepath.ToSlash(filePath)
+	if !strings.Contains(glob, "/") {
+		ok, _ := path.Match(glob, path.Base(filePath))
+		return ok
+	}
+
+	re, ok := globCache.Load(glob)
+	if !ok {
+		compiled, err := globToRegexp(glob)
+		if err != nil {
+			return false
+		}
+		re, _ = globCache.LoadOrStore(glob, compiled)
+	}
+	return re.(*regexp.Regexp).MatchString(filePath)
+}
+
+// globToRegexp convertit un glob en regex ancrée sur une frontière de dossier
+func globToRegexp(glob string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString(`(?:^|/)`)
+	glob = strings.TrimPrefix(glob, "/")
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && i+1 < len(glob) && glob[i+1] == '*':
+			i++
+			if i+1 < len(glob) && glob[i+1] == '/' {
+				i++
+				b.WriteString(`(?:.*/)?`)
+			} else {
+				b.WriteString(`.*`)
+			}
+		case c == '*':
+			b.WriteString(`[^/]*`)
+		case c == '?':
+			b.WriteString(`[^/]`)
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString(`$`)
+	return regexp.Compile(b.String())
+}
@@ -0,0 +1,150 @@
+package patterns
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Severity est la gravité d'un secret (ou le niveau par défaut d'une règle)
+type Severity string
+
+// Niveaux de gravité, du plus grave au moins grave
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+	SeverityInfo     Severity = "info" // Valeur publique par conception (ex: clé anon Supabase)
+)
+
+// severityLevels liste les niveaux de gravité par ordre décroissant
+var severityLevels = []Severity{SeverityCritical, SeverityHigh, SeverityMedium, SeverityLow, SeverityInfo}
+
+// Severities retourne les niveaux de gravité par ordre décroissant
+func Severities() []Severity {
+	return append([]Severity(nil), severityLevels...)
+}
+
+// ParseSeverity lit un niveau de gravité (insensible à la casse)
+func ParseSeverity(s string) (Severity, error) {
+	severity := Severity(strings.ToLower(strings.TrimSpace(s)))
+	if !severity.IsValid() {
+		return "", fmt.Errorf("sévérité invalide %q (attendu: %s)", s, severityNames())
+	}
+	return severity, nil
+}
+
+// severityNames liste les niveaux de gravité pour les messages d'erreur
+func severityNames() string {
+	names := make([]string, len(severityLevels))
+	for i, level := range severityLevels {
+		names[i] = string(level)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Rank retourne le rang d'un niveau de gravité (0 = critical); un niveau inconnu est classé en dernier
+func (s Severity) Rank() int {
+	for i, level := range severityLevels {
+		if level == s {
+			return i
+		}
+	}
+	return len(severityLevels)
+}
+
+// IsValid indique si un niveau de gravité est reconnu
+func (s Severity) IsValid() bool {
+	return s.Rank() < len(severityLevels)
+}
+
+// AtLeast indique si la gravité atteint un niveau minimal
+func (s Severity) AtLeast(min Severity) bool {
+	return s.Rank() <= min.Rank()
+}
+
+// Confidence est la confiance dans le fait qu'une correspondance soit un vrai secret
+type Confidence string
+
+// Niveaux de confiance, du plus sûr au moins sûr
+const (
+	ConfidenceHigh   Confidence = "high"   // Format distinctif (préfixe, en-tête) ou checksum valide
+	ConfidenceMedium Confidence = "medium" // Format reconnu grâce au contexte (nom de variable, service)
+	ConfidenceLow    Confidence = "low"    // Règle générique ou checksum incorrect
+)
+
+// confidenceLevels liste les niveaux de confiance par ordre décroissant
+var confidenceLevels = []Confidence{ConfidenceHigh, ConfidenceMedium, ConfidenceLow}
+
+// ParseConfidence lit un niveau de confiance (insensible à la casse)
+func ParseConfidence(s string) (Confidence, error) {
+	confidence := Confidence(strings.ToLower(strings.TrimSpace(s)))
+	if !confidence.IsValid() {
+		return "", fmt.Errorf("confiance invalide %q (attendu: %s)", s, confidenceNames())
+	}
+	return confidence, nil
+}
+
+// confidenceNames liste les niveaux de confiance pour les messages d'erreur
+func confidenceNames() string {
+	names := make([]string, len(confidenceLevels))
+	for i, level := range confidenceLevels {
+		names[i] = string(level)
+	}
+	return strings.Join(names, ", ")
+}
+
+// Rank retourne le rang d'un niveau de confiance (0 = high); un niveau inconnu est classé en dernier
+func (c Confidence) Rank() int {
+	for i, level := range confidenceLevels {
+		if level == c {
+			return i
+		}
+	}
+	return len(confidenceLevels)
+}
+
+// IsValid indique si un niveau de confiance est reconnu
+func (c Confidence) IsValid() bool {
+	return c.Rank() < len(confidenceLevels)
+}
+
+// SeverityOverride remplace la sévérité des secrets d'une règle et/ou des fichiers
+// correspondant à des globs (ex: "low" pour generic-credential, "info" sous docs/**)
+type SeverityOverride struct {
+	Rule     string   // Identifiant de la règle (vide = toutes les règles)
+	Paths    []string // Globs des chemins concernés (vide = partout)
+	Severity Severity
+}
+
+// Validate vérifie la sévérité, les globs et la règle visée par un remplacement
+func (o SeverityOverride) Validate(list []Pattern) error {
+	if !o.Severity.IsValid() {
+		return fmt.Errorf("sévérité invalide %q (attendu: %s)", o.Severity, severityNames())
+	}
+	if o.Rule == "" && len(o.Paths) == 0 {
+		return fmt.Errorf("'rule' ou 'paths' requis")
+	}
+	if o.Rule != "" {
+		if _, ok := FindByID(list, o.Rule); !ok {
+			return fmt.Errorf("règle inconnue: %s", o.Rule)
+		}
+	}
+	return validateGlobs(o.Paths)
+}
+
+// Matches indique si le remplacement s'applique à un secret d'une règle dans un fichier
+func (o SeverityOverride) Matches(ruleID, filePath string) bool {
+	if o.Rule != "" && o.Rule != ruleID {
+		return false
+	}
+	if len(o.Paths) == 0 {
+		return true
+	}
+	for _, glob := range o.Paths {
+		if MatchGlob(glob, filePath) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package scan
+
+import "github.com/TALLHAMADOU/goleaks/patterns"
+
+// Statut du checksum d'un secret (vide si la règle n'a pas de validateur)
+const (
+	ChecksumValid   = "valid"   // Checksum correct: token réel avec une confiance élevée
+	ChecksumInvalid = "invalid" // Checksum incorrect: token aléatoire, fixture de test ou faute de frappe
+)
+
+// checkChecksum valide hors ligne le checksum d'un secret. Un checksum correct élève
+// la confiance à high; un checksum incorrect abaisse la sévérité et la confiance à low,
+// ou écarte le secret (retourne false) si drop est vrai ou si le validateur est strict
+// (numéros de carte, IBAN...).
+func checkChecksum(secret *Secret, validator string, drop bool) bool {
+	if patterns.ValidChecksum(validator, secret.OriginalMatch) {
+		secret.Checksum = ChecksumValid
+		secret.Confidence = patterns.ConfidenceHigh
+		return true
+	}
+	if drop || patterns.IsStrictValidator(validator) {
+		return false
+	}
+	secret.Checksum = ChecksumInvalid
+	secret.Severity = patterns.SeverityLow
+	secret.Confidence = patterns.ConfidenceLow
+	return true
+}
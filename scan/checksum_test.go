@@ -0,0 +1,61 @@
+package scan
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// Le checksum d'un token GitHub est validé hors ligne: un token valide est en confiance
+// élevée, un token invalide est déclassé ou écarté
+func TestChecksumValidation(t *testing.T) {
+	valid, err := patterns.ExpandExample("{{checksum:ghp_}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalid := "ghp_" + strings.Repeat("Ab3", 12)
+	content := "GITHUB_TOKEN=" + valid + "\nOTHER_TOKEN=" + invalid + "\n"
+
+	opts := DefaultScanOptions()
+	secrets, err := ScanReader("ci.env", strings.NewReader(content), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 2 {
+		t.Fatalf("%d secrets (%s), attendu 2", len(secrets), ruleIDs(secrets))
+	}
+	if s := secrets[0]; s.RuleID != "github-pat" || s.Checksum != ChecksumValid || s.Confidence != patterns.ConfidenceHigh {
+		t.Errorf("token valide: règle %s, checksum %q, confiance %s", s.RuleID, s.Checksum, s.Confidence)
+	}
+	if s := secrets[1]; s.Checksum != ChecksumInvalid || s.Severity != patterns.SeverityLow || s.Confidence != patterns.ConfidenceLow {
+		t.Errorf("token invalide: checksum %q, sévérité %s, confiance %s", s.Checksum, s.Severity, s.Confidence)
+	}
+
+	opts.DropInvalidChecksums = true
+	secrets, err = ScanReader("ci.env", strings.NewReader(content), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 || secrets[0].OriginalMatch != valid {
+		t.Errorf("DropInvalidChecksums: %d secrets (%s), attendu le seul token valide", len(secrets), ruleIDs(secrets))
+	}
+}
+
+// Un numéro du pack pii dont la clé est fausse est toujours écarté
+func TestStrictChecksumValidation(t *testing.T) {
+	list, err := patterns.PackPatterns([]string{patterns.PackPII})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := DefaultScanOptions()
+	opts.Patterns = list
+	content := "card_number = \"4539 1234 5678 9012\"\niban = GB82WEST12345698765433\niban = GB82WEST12345698765432\n"
+	secrets, err := ScanReader("billing.env", strings.NewReader(content), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 || secrets[0].RuleID != "iban" || secrets[0].Line != 3 || secrets[0].Checksum != ChecksumValid {
+		t.Errorf("secrets %+v, attendu le seul IBAN valide (ligne 3)", secrets)
+	}
+}
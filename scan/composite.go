@@ -0,0 +1,190 @@
+package scan
+
+import (
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// CompanionMatch représente un secret compagnon rapporté avec le secret principal
+type CompanionMatch struct {
+	Name          string
+	Line          int
+	Match         string // Secret compagnon masqué
+	OriginalMatch string
+}
+
+// companionRef relie un compagnon indexé dans le matcher à son pattern principal
+type companionRef struct {
+	pattern   int
+	companion int
+}
+
+// companionHit est une correspondance d'un compagnon dans le fichier
+type companionHit struct {
+	line       int
+	start, end int // Position du compagnon dans la ligne
+	secret     string
+}
+
+// secretSpan est la position d'un secret du fichier dans sa ligne
+type secretSpan struct {
+	line       int
+	start, end int
+}
+
+// pendingComposite est un secret principal en attente de ses compagnons
+type pendingComposite struct {
+	secret  int // Index dans la liste des secrets du fichier
+	pattern int // Index du pattern dans le matcher
+}
+
+// compositeTracker collecte les compagnons d'un fichier et les associe aux secrets principaux
+type compositeTracker struct {
+	matcher  *patternMatcher
+	hits     map[companionRef][]companionHit
+	pending  []pendingComposite
+	selected []bool
+	spans    map[int]secretSpan // Positions des secrets du fichier, par index
+}
+
+func newCompositeTracker(matcher *patternMatcher) *compositeTracker {
+	t := &compositeTracker{matcher: matcher}
+	if matcher.companions != nil {
+		t.hits = make(map[companionRef][]companionHit)
+		t.selected = make([]bool, len(matcher.companions.patterns))
+		t.spans = make(map[int]secretSpan)
+	}
+	return t
+}
+
+// scanLine recherche les compagnons présents sur une ligne (ou une fenêtre de la ligne
+// commençant à offset)
+func (t *compositeTracker) scanLine(line string, lineNum int, offset int) {
+	if t.matcher.companions == nil {
+		return
+	}
+	for i, candidate := range t.matcher.companions.candidates(line, t.selected) {
+		if !candidate {
+			continue
+		}
+		ref := t.matcher.companionRefs[i]
+		for _, m := range t.matcher.companions.patterns[i].FindAll(line) {
+			t.hits[ref] = append(t.hits[ref], companionHit{
+				line:   lineNum,
+				start:  offset + m.Start,
+				end:    offset + m.End,
+				secret: m.Secret,
+			})
+		}
+	}
+}
+
+// track enregistre un secret principal dont le pattern déclare des compagnons
+func (t *compositeTracker) track(secret int, pattern int) {
+	t.pending = append(t.pending, pendingComposite{secret: secret, pattern: pattern})
+}
+
+// locate enregistre la position d'un secret du fichier: un secret rapporté comme
+// compagnon n'est pas rapporté une seconde fois (ex: par la règle générique)
+func (t *compositeTracker) locate(secret int, line int, start, end int) {
+	if t.spans != nil {
+		t.spans[secret] = secretSpan{line: line, start: start, end: end}
+	}
+}
+
+// resolve attache les compagnons trouvés aux secrets principaux et retire ceux
+// dont un compagnon obligatoire est absent
+func (t *compositeTracker) resolve(secrets []Secret) []Secret {
+	if len(t.pending) == 0 {
+		return secrets
+	}
+
+	dropped := make(map[int]bool)
+	primary := make(map[int]bool, len(t.pending))
+	var attached []companionHit
+	for _, p := range t.pending {
+		primary[p.secret] = true
+	}
+	for _, p := range t.pending {
+		secret := &secrets[p.secret]
+		for c, companion := range t.matcher.patterns[p.pattern].Companions {
+			hit, ok := nearestHit(t.hits[companionRef{pattern: p.pattern, companion: c}], secret.Line, companion.WithinLines)
+			if !ok {
+				if companion.Required {
+					dropped[p.secret] = true
+				}
+				continue
+			}
+			attached = append(attached, hit)
+			secret.Companions = append(secret.Companions, CompanionMatch{
+				Name:          companion.Name,
+				Line:          hit.line,
+				Match:         maskSecret(hit.secret),
+				OriginalMatch: hit.secret,
+			})
+		}
+	}
+
+	// Les secrets trouvés à la place d'un compagnon rattaché sont des doublons
+	for i, span := range t.spans {
+		if !primary[i] && overlapsHit(attached, span) {
+			dropped[i] = true
+		}
+	}
+
+	if len(dropped) == 0 {
+		return secrets
+	}
+	kept := secrets[:0]
+	for i, secret := range secrets {
+		if !dropped[i] {
+			kept = append(kept, secret)
+		}
+	}
+	return kept
+}
+
+// overlapsHit indique si un secret chevauche l'un des compagnons
+func overlapsHit(hits []companionHit, span secretSpan) bool {
+	for _, hit := range hits {
+		if hit.line == span.line && span.start < hit.end && hit.start < span.end {
+			return true
+		}
+	}
+	return false
+}
+
+// nearestHit retourne la correspondance la plus proche d'une ligne, dans la limite donnée (0 = sans limite)
+func nearestHit(hits []companionHit, line int, within int) (companionHit, bool) {
+	best, bestDist := companionHit{}, -1
+	for _, hit := range hits {
+		dist := hit.line - line
+		if dist < 0 {
+			dist = -dist
+		}
+		if within > 0 && dist > within {
+			continue
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = hit, dist
+		}
+	}
+	return best, bestDist >= 0
+}
+
+// companionPatterns transforme les compagnons des patterns en patterns indexables par le matcher
+func companionPatterns(list []patterns.Pattern) ([]patterns.Pattern, []companionRef) {
+	var companions []patterns.Pattern
+	var refs []companionRef
+	for i, p := range list {
+		for c, companion := range p.Companions {
+			companions = append(companions, patterns.Pattern{
+				ID:       p.ID + "#" + companion.Name,
+				Service:  p.Service,
+				Keywords: companion.Keywords,
+				Regex:    companion.Regex,
+			})
+			refs = append(refs, companionRef{pattern: i, companion: c})
+		}
+	}
+	return companions, refs
+}
@@ -0,0 +1,53 @@
+package scan
+
+import (
+	"context"
+	"strings"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// ExampleFailure décrit un exemple de règle dont le résultat ne correspond pas à l'attendu
+type ExampleFailure struct {
+	RuleID      string
+	Example     string
+	ShouldMatch bool  // true pour un exemple positif non détecté, false pour un exemple négatif détecté
+	Err         error // Erreur de lecture de l'exemple (rare)
+}
+
+// CheckExamples vérifie les exemples positifs et négatifs de chaque règle.
+// Chaque exemple est scanné comme un fichier ne contenant que la règle testée,
+// sans tenir compte de sa portée (chemins, types de fichiers), afin d'exercer
+// les mêmes filtres que le scan: groupe secret, entropie, allowlist de valeurs,
+// blocs multi-lignes et compagnons.
+func CheckExamples(list []patterns.Pattern) []ExampleFailure {
+	opts := DefaultScanOptions()
+	var failures []ExampleFailure
+
+	for _, p := range list {
+		if len(p.Examples) == 0 && len(p.NegativeExamples) == 0 {
+			continue
+		}
+
+		// Règle isolée et sans restriction de portée
+		p.Paths, p.FileTypes, p.PathRegex = nil, nil, nil
+		p.Allowlist.Paths, p.Allowlist.PathRegex = nil, nil
+		p.Fallback = false
+		matcher := newPatternMatcher([]patterns.Pattern{p})
+
+		check := func(example string, shouldMatch bool) {
+			secrets, err := scanReader(context.Background(), strings.NewReader(example), "", opts, matcher)
+			if err != nil || (len(secrets) > 0) != shouldMatch {
+				failures = append(failures, ExampleFailure{RuleID: p.ID, Example: example, ShouldMatch: shouldMatch, Err: err})
+			}
+		}
+		for _, example := range p.Examples {
+			check(example, true)
+		}
+		for _, example := range p.NegativeExamples {
+			check(example, false)
+		}
+	}
+
+	return failures
+}
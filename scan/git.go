@@ -2,6 +2,7 @@ package scan
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -18,13 +19,18 @@ type GitDiffFile struct {
 
 // GetGitDiffFiles récupère les fichiers modifiés via git diff
 func GetGitDiffFiles(repoPath string) ([]GitDiffFile, error) {
+	return getGitDiffFiles(context.Background(), repoPath)
+}
+
+// getGitDiffFiles récupère les fichiers modifiés; les commandes git sont arrêtées si le contexte est annulé
+func getGitDiffFiles(ctx context.Context, repoPath string) ([]GitDiffFile, error) {
 	// Vérifier si on est dans un repo Git
 	if !isGitRepo(repoPath) {
 		return nil, fmt.Errorf("le répertoire n'est pas un dépôt Git")
 	}
 
 	// Récupérer les fichiers modifiés (unstaged + staged)
-	files, err := getModifiedFiles(repoPath)
+	files, err := getModifiedFiles(ctx, repoPath)
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +42,7 @@ func GetGitDiffFiles(repoPath string) ([]GitDiffFile, error) {
 	// Récupérer le diff pour chaque fichier
 	diffFiles := make([]GitDiffFile, 0, len(files))
 	for _, file := range files {
-		diffContent, err := getFileDiff(repoPath, file)
+		diffContent, err := getFileDiff(ctx, repoPath, file)
 		if err != nil {
 			continue // Ignorer les erreurs silencieusement
 		}
@@ -62,9 +68,9 @@ func isGitRepo(path string) bool {
 }
 
 // getModifiedFiles récupère la liste des fichiers modifiés
-func getModifiedFiles(repoPath string) ([]string, error) {
+func getModifiedFiles(ctx context.Context, repoPath string) ([]string, error) {
 	// git diff --name-only (unstaged + staged)
-	cmd := exec.Command("git", "diff", "--name-only", "--diff-filter=ACMR")
+	cmd := exec.CommandContext(ctx, "git", "diff", "--name-only", "--diff-filter=ACMR")
 	cmd.Dir = repoPath
 	output, err := cmd.Output()
 	if err != nil {
@@ -81,7 +87,7 @@ func getModifiedFiles(repoPath string) ([]string, error) {
 	}
 
 	// Aussi récupérer les fichiers staged
-	cmd2 := exec.Command("git", "diff", "--cached", "--name-only", "--diff-filter=ACMR")
+	cmd2 := exec.CommandContext(ctx, "git", "diff", "--cached", "--name-only", "--diff-filter=ACMR")
 	cmd2.Dir = repoPath
 	output2, err2 := cmd2.Output()
 	if err2 == nil {
@@ -108,8 +114,8 @@ func getModifiedFiles(repoPath string) ([]string, error) {
 }
 
 // getFileDiff récupère le diff d'un fichier spécifique
-func getFileDiff(repoPath string, filePath string) (string, error) {
-	cmd := exec.Command("git", "diff", "--unified=0", filePath)
+func getFileDiff(ctx context.Context, repoPath string, filePath string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", "diff", "--unified=0", filePath)
 	cmd.Dir = repoPath
 	output, err := cmd.Output()
 	if err != nil {
@@ -117,7 +123,7 @@ func getFileDiff(repoPath string, filePath string) (string, error) {
 	}
 
 	// Aussi vérifier les changements staged
-	cmd2 := exec.Command("git", "diff", "--cached", "--unified=0", filePath)
+	cmd2 := exec.CommandContext(ctx, "git", "diff", "--cached", "--unified=0", filePath)
 	cmd2.Dir = repoPath
 	output2, err2 := cmd2.Output()
 	if err2 == nil && len(output2) > 0 {
@@ -175,25 +181,45 @@ func parseDiffLines(diffContent string) []int {
 
 // ScanGitDiff scanne uniquement les changements Git
 func ScanGitDiff(repoPath string, opts ScanOptions) (*ScanResult, error) {
-	result := &ScanResult{
-		Secrets: []Secret{},
-		Files:   0,
-		Errors:  []string{},
-	}
+	return ScanGitDiffContext(context.Background(), repoPath, opts)
+}
+
+// ScanGitDiffContext scanne les changements Git en respectant l'annulation du contexte.
+// Comme ScanDirectoryContext, un scan interrompu retourne le résultat partiel, marqué
+// Incomplete, avec l'erreur du contexte.
+func ScanGitDiffContext(ctx context.Context, repoPath string, opts ScanOptions) (*ScanResult, error) {
+	var c collector
+	summary, err := StreamGitDiff(ctx, repoPath, opts, c.finding, c.error)
+	return c.result(ctx, summary, err)
+}
+
+// StreamGitDiff scanne les changements Git et transmet les secrets des lignes modifiées
+// et les erreurs aux handlers (nil = ignorés) au fur et à mesure, comme StreamDirectory.
+// Les fichiers sont scannés un par un, dans l'ordre de git diff.
+func StreamGitDiff(ctx context.Context, repoPath string, opts ScanOptions, onFinding FindingHandler, onError ErrorHandler) (StreamSummary, error) {
+	var summary StreamSummary
 
 	// Récupérer les fichiers modifiés
-	diffFiles, err := GetGitDiffFiles(repoPath)
+	diffFiles, err := getGitDiffFiles(ctx, repoPath)
 	if err != nil {
-		return nil, err
+		return summary, err
 	}
 
 	absRepoPath, err := filepath.Abs(repoPath)
 	if err != nil {
-		return nil, err
+		return summary, err
+	}
+
+	if opts.Root == "" {
+		opts.Root = absRepoPath
 	}
 
 	// Scanner chaque fichier modifié
+	matcher := newPatternMatcher(opts.ActivePatterns())
 	for _, diffFile := range diffFiles {
+		if ctx.Err() != nil {
+			break
+		}
 		fullPath := filepath.Join(absRepoPath, diffFile.Path)
 
 		// Vérifier si le fichier doit être scanné
@@ -206,10 +232,10 @@ func ScanGitDiff(repoPath string, opts ScanOptions) (*ScanResult, error) {
 		}
 
 		// Scanner le fichier complet (plus simple que de scanner seulement les lignes modifiées)
-		secrets, scanErr := ScanFile(fullPath, opts)
-		if scanErr != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("Erreur scan %s: %v", diffFile.Path, scanErr))
-			continue
+		secrets, scanErr := scanFile(ctx, fullPath, opts, matcher)
+		// Un fichier interrompu (délai par fichier) garde les secrets trouvés avant l'interruption
+		if scanErr != nil && ctx.Err() == nil && onError != nil {
+			onError(&FileError{Op: "scan", Path: diffFile.Path, Err: scanErr})
 		}
 
 		// Filtrer les secrets pour ne garder que ceux sur les lignes modifiées
@@ -217,7 +243,7 @@ func ScanGitDiff(repoPath string, opts ScanOptions) (*ScanResult, error) {
 			filteredSecrets := make([]Secret, 0)
 			for _, secret := range secrets {
 				for _, lineNum := range diffFile.Lines {
-					if secret.Line == lineNum {
+					if lineNum >= secret.Line && lineNum <= secret.EndLine {
 						filteredSecrets = append(filteredSecrets, secret)
 						break
 					}
@@ -227,10 +253,14 @@ func ScanGitDiff(repoPath string, opts ScanOptions) (*ScanResult, error) {
 		}
 
 		if len(secrets) > 0 {
-			result.Files++
-			result.Secrets = append(result.Secrets, secrets...)
+			summary.Files++
+			if onFinding != nil {
+				for _, secret := range secrets {
+					onFinding(secret)
+				}
+			}
 		}
 	}
 
-	return result, nil
+	return summary, ctx.Err()
 }
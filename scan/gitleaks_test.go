@@ -0,0 +1,101 @@
+package scan
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// Les regex de chemins importées de gitleaks sont ancrées sur le chemin relatif à la
+// racine du scan
+func TestGitleaksPathRegexRelativeToRoot(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "gitleaks.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rf, _, err := patterns.ImportGitleaks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	list, err := patterns.CompileRules(rf.Rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	content := "TOKEN=demo_k3v9x2m7q4w8z1n6b5c0r2t8\nKEY=dk_Q7wE3rT9yU1iO5pA2sD8fG4h\n"
+	for _, name := range []string{"config/app.env", "app.env", "a.env", "sub/a.env"} {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opts := DefaultScanOptions()
+	opts.Patterns = list
+	result, err := ScanDirectory(root, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, s := range result.Secrets {
+		rel, _ := filepath.Rel(root, s.File)
+		got = append(got, filepath.ToSlash(rel)+":"+s.RuleID)
+	}
+	sort.Strings(got)
+	want := []string{
+		"app.env:demo-api-key",
+		"config/app.env:demo-api-key",
+		"config/app.env:demo-config-token",
+		"sub/a.env:demo-api-key",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("secrets %v, attendu %v", got, want)
+	}
+}
+
+// Sans secretGroup, le secret d'une règle gitleaks importée est le premier groupe de
+// capture: l'entropie et le masquage portent sur la valeur et non sur l'affectation
+func TestGitleaksDefaultSecretGroup(t *testing.T) {
+	data := []byte(`
+[[rules]]
+id = "acme-key"
+regex = '''ACME_KEY\s*=\s*'([a-z0-9]{24})'''
+entropy = 3.5
+keywords = ["acme_key"]
+`)
+	rf, _, err := patterns.ImportGitleaks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rf.Rules[0].SecretGroup != 1 {
+		t.Errorf("secret_group %d, attendu 1", rf.Rules[0].SecretGroup)
+	}
+	list, err := patterns.CompileRules(rf.Rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts := DefaultScanOptions()
+	opts.Patterns = list
+	content := "ACME_KEY = 'k3v9x2m7q4w8z1n6b5c0r2t8'\nACME_KEY = 'aaaaaaaaaaaabbbbbbbbbbbb'\n"
+	secrets, err := ScanReader("app.env", strings.NewReader(content), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(secrets) != 1 {
+		t.Fatalf("%d secrets, attendu 1 (la valeur de faible entropie est écartée)", len(secrets))
+	}
+	if got := secrets[0].OriginalMatch; got != "k3v9x2m7q4w8z1n6b5c0r2t8" {
+		t.Errorf("OriginalMatch %q, attendu la valeur seule", got)
+	}
+}
@@ -0,0 +1,119 @@
+package scan
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// JWTClaims contient l'en-tête et les claims utiles d'un JWT, décodés hors ligne
+// (la signature n'est pas vérifiée)
+type JWTClaims struct {
+	Alg     string
+	Iss     string
+	Role    string
+	Exp     int64 // Date d'expiration (timestamp Unix, 0 = absente)
+	Aud     []string
+	Expired bool
+}
+
+// ExpiresAt retourne la date d'expiration du token (zéro si absente)
+func (c JWTClaims) ExpiresAt() time.Time {
+	if c.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(c.Exp, 0).UTC()
+}
+
+// audience accepte le claim "aud" sous forme de chaîne ou de liste
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*a = list
+	return nil
+}
+
+// DecodeJWT décode l'en-tête et les claims d'un JWT sans vérifier sa signature
+func DecodeJWT(token string, now time.Time) (*JWTClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("JWT invalide: %d partie(s) au lieu de 3", len(parts))
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("en-tête JWT invalide: %v", err)
+	}
+
+	var payload struct {
+		Iss  string   `json:"iss"`
+		Role string   `json:"role"`
+		Exp  float64  `json:"exp"`
+		Aud  audience `json:"aud"`
+	}
+	if err := decodeJWTPart(parts[1], &payload); err != nil {
+		return nil, fmt.Errorf("claims JWT invalides: %v", err)
+	}
+
+	claims := &JWTClaims{
+		Alg:  header.Alg,
+		Iss:  payload.Iss,
+		Role: payload.Role,
+		Exp:  int64(payload.Exp),
+		Aud:  payload.Aud,
+	}
+	claims.Expired = claims.Exp > 0 && now.After(claims.ExpiresAt())
+	return claims, nil
+}
+
+// decodeJWTPart décode une partie base64url (avec ou sans padding) d'un JWT
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(part, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// jwtSeverity détermine la sévérité d'un JWT selon ses claims: expiré = low,
+// service_role = critical, anon (clé publique par conception) = info
+func jwtSeverity(claims *JWTClaims, defaultSeverity patterns.Severity) patterns.Severity {
+	switch {
+	case claims.Expired:
+		return patterns.SeverityLow
+	case claims.Role == "service_role":
+		return patterns.SeverityCritical
+	case claims.Role == "anon":
+		return patterns.SeverityInfo
+	default:
+		return defaultSeverity
+	}
+}
+
+// analyzeJWT décode un secret JWT et ajuste sa sévérité; un token décodé est un vrai JWT
+// (confiance élevée), un token non décodable est conservé tel quel
+func analyzeJWT(secret *Secret, _ patterns.Match) bool {
+	claims, err := DecodeJWT(secret.OriginalMatch, time.Now())
+	if err != nil {
+		return true
+	}
+	secret.JWT = claims
+	secret.Severity = jwtSeverity(claims, secret.Severity)
+	secret.Confidence = patterns.ConfidenceHigh
+	return true
+}
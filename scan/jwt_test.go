@@ -0,0 +1,120 @@
+package scan
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// jwtToken construit un JWT de test (signature factice) à partir de ses claims JSON
+func jwtToken(claims string) string {
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." +
+		enc.EncodeToString([]byte(claims)) + ".c2lnbmF0dXJlLWZhY3RpY2UtcG91ci1sZXMtdGVzdHMtZ29sZWFrcw"
+}
+
+func TestDecodeJWT(t *testing.T) {
+	now := time.Unix(1800000000, 0)
+	claims, err := DecodeJWT(jwtToken(`{"iss":"supabase","role":"service_role","exp":2000000000,"aud":"authenticated"}`), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Alg != "HS256" || claims.Iss != "supabase" || claims.Role != "service_role" || claims.Exp != 2000000000 || claims.Expired {
+		t.Errorf("claims %+v", claims)
+	}
+	if len(claims.Aud) != 1 || claims.Aud[0] != "authenticated" {
+		t.Errorf("aud %v, attendu [authenticated]", claims.Aud)
+	}
+	if !claims.ExpiresAt().Equal(time.Unix(2000000000, 0)) {
+		t.Errorf("ExpiresAt %v", claims.ExpiresAt())
+	}
+
+	// aud en liste, exp dépassé, partie avec padding
+	token := jwtToken(`{"aud":["api","web"],"exp":1700000000}`)
+	parts := strings.Split(token, ".")
+	parts[0] += strings.Repeat("=", (4-len(parts[0])%4)%4)
+	claims, err = DecodeJWT(strings.Join(parts, "."), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(claims.Aud) != 2 || !claims.Expired {
+		t.Errorf("claims %+v, attendu 2 audiences et un token expiré", claims)
+	}
+
+	// Sans exp, le token n'expire pas
+	claims, err = DecodeJWT(jwtToken(`{"role":"anon"}`), now)
+	if err != nil || claims.Expired || !claims.ExpiresAt().IsZero() {
+		t.Errorf("claims %+v, erreur %v", claims, err)
+	}
+}
+
+func TestDecodeJWTMalformed(t *testing.T) {
+	valid := strings.Split(jwtToken(`{"role":"anon"}`), ".")
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{"deux parties", valid[0] + "." + valid[1], "2 partie(s)"},
+		{"en-tête non base64", "!!!." + valid[1] + "." + valid[2], "en-tête JWT invalide"},
+		{"en-tête non JSON", base64.RawURLEncoding.EncodeToString([]byte("alg")) + "." + valid[1] + "." + valid[2], "en-tête JWT invalide"},
+		{"claims non JSON", valid[0] + "." + base64.RawURLEncoding.EncodeToString([]byte("{role")) + "." + valid[2], "claims JWT invalides"},
+		{"aud invalide", valid[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"aud":42}`)) + "." + valid[2], "claims JWT invalides"},
+	}
+	for _, tt := range tests {
+		_, err := DecodeJWT(tt.token, time.Now())
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: erreur %v, attendu %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestJWTSeverity(t *testing.T) {
+	tests := []struct {
+		claims JWTClaims
+		want   patterns.Severity
+	}{
+		{JWTClaims{Role: "service_role"}, patterns.SeverityCritical},
+		{JWTClaims{Role: "anon"}, patterns.SeverityInfo},
+		{JWTClaims{Role: "authenticated"}, patterns.SeverityHigh},
+		{JWTClaims{Role: "service_role", Expired: true}, patterns.SeverityLow},
+		{JWTClaims{Role: "anon", Expired: true}, patterns.SeverityLow},
+	}
+	for _, tt := range tests {
+		if got := jwtSeverity(&tt.claims, patterns.SeverityHigh); got != tt.want {
+			t.Errorf("%+v: sévérité %s, attendu %s", tt.claims, got, tt.want)
+		}
+	}
+}
+
+// Les secrets JWT détectés portent leurs claims décodés et une sévérité dérivée
+func TestScanJWTFindings(t *testing.T) {
+	tests := []struct {
+		claims string
+		want   patterns.Severity
+	}{
+		{`{"iss":"supabase","ref":"abcdefghijklmnopqrst","role":"service_role","exp":4000000000}`, patterns.SeverityCritical},
+		{`{"iss":"supabase","ref":"abcdefghijklmnopqrst","role":"anon","exp":4000000000}`, patterns.SeverityInfo},
+		{`{"iss":"supabase","ref":"abcdefghijklmnopqrst","role":"service_role","exp":1500000000}`, patterns.SeverityLow},
+	}
+	for _, tt := range tests {
+		token := jwtToken(tt.claims)
+		secrets, err := ScanBytes("app.env", []byte("SUPABASE_KEY="+token+"\n"), DefaultScanOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(secrets) != 1 {
+			t.Fatalf("%s: %d secrets (%s), attendu 1", tt.claims, len(secrets), ruleIDs(secrets))
+		}
+		s := secrets[0]
+		if s.RuleID != "supabase-jwt" || s.JWT == nil || s.JWT.Iss != "supabase" {
+			t.Fatalf("%s: règle %s, claims %+v", tt.claims, s.RuleID, s.JWT)
+		}
+		if s.Severity != tt.want || s.Confidence != patterns.ConfidenceHigh || s.Risk != string(tt.want) {
+			t.Errorf("%s: sévérité %s, confiance %s, risque %s, attendu %s/high", tt.claims, s.Severity, s.Confidence, s.Risk, tt.want)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package scan
+
+import (
+	"strings"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// acNode est un état de l'automate Aho-Corasick (transitions complètes sur les octets)
+type acNode struct {
+	next [256]int32
+	out  []int // Index des mots-clés reconnus dans cet état
+}
+
+// keywordAutomaton recherche simultanément tous les mots-clés dans une ligne en une seule passe.
+// La recherche est insensible à la casse (ASCII).
+type keywordAutomaton struct {
+	nodes []acNode
+}
+
+// newKeywordAutomaton construit l'automate pour une liste de mots-clés
+func newKeywordAutomaton(keywords []string) *keywordAutomaton {
+	ac := &keywordAutomaton{nodes: []acNode{{}}}
+
+	// Construction du trie
+	for i, kw := range keywords {
+		state := int32(0)
+		for j := 0; j < len(kw); j++ {
+			c := lowerASCII(kw[j])
+			if ac.nodes[state].next[c] == 0 {
+				ac.nodes = append(ac.nodes, acNode{})
+				ac.nodes[state].next[c] = int32(len(ac.nodes) - 1)
+			}
+			state = ac.nodes[state].next[c]
+		}
+		ac.nodes[state].out = append(ac.nodes[state].out, i)
+	}
+
+	// Liens d'échec calculés en largeur, puis transformés en transitions directes
+	fail := make([]int32, len(ac.nodes))
+	queue := make([]int32, 0, len(ac.nodes))
+	for c := 0; c < 256; c++ {
+		if child := ac.nodes[0].next[c]; child != 0 {
+			queue = append(queue, child)
+		}
+	}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+		ac.nodes[state].out = append(ac.nodes[state].out, ac.nodes[fail[state]].out...)
+		for c := 0; c < 256; c++ {
+			child := ac.nodes[state].next[c]
+			if child != 0 {
+				fail[child] = ac.nodes[fail[state]].next[c]
+				queue = append(queue, child)
+			} else {
+				ac.nodes[state].next[c] = ac.nodes[fail[state]].next[c]
+			}
+		}
+	}
+
+	return ac
+}
+
+// lowerASCII met un octet ASCII en minuscule
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// patternMatcher sélectionne les patterns à évaluer sur une ligne grâce aux mots-clés
+type patternMatcher struct {
+	patterns    []patterns.Pattern
+	automaton   *keywordAutomaton
+	kwPatterns  [][]int // Mot-clé -> index des patterns qui le déclarent
+	always      []int   // Patterns sans mot-clé, évalués sur chaque ligne
+	order       []int   // Ordre d'évaluation: règles spécifiques d'abord, règles génériques (Fallback) ensuite
+	specificity []int   // Spécificité de chaque pattern (voir resolveOverlaps)
+
+	companions    *patternMatcher // Compagnons des règles composites (nil si aucun)
+	companionRefs []companionRef
+}
+
+// newPatternMatcher prépare le préfiltre par mots-clés pour un jeu de patterns
+func newPatternMatcher(list []patterns.Pattern) *patternMatcher {
+	m := &patternMatcher{patterns: list}
+
+	var fallbacks []int
+	m.specificity = make([]int, len(list))
+	for i, p := range list {
+		m.specificity[i] = patternSpecificity(p)
+		if p.Fallback {
+			fallbacks = append(fallbacks, i)
+		} else {
+			m.order = append(m.order, i)
+		}
+	}
+	m.order = append(m.order, fallbacks...)
+
+	keywordIndex := make(map[string]int)
+	var keywords []string
+	for i, p := range list {
+		if len(p.Keywords) == 0 {
+			m.always = append(m.always, i)
+			continue
+		}
+		for _, kw := range p.Keywords {
+			kw = strings.ToLower(kw)
+			if kw == "" {
+				continue
+			}
+			idx, ok := keywordIndex[kw]
+			if !ok {
+				idx = len(keywords)
+				keywordIndex[kw] = idx
+				keywords = append(keywords, kw)
+				m.kwPatterns = append(m.kwPatterns, nil)
+			}
+			m.kwPatterns[idx] = append(m.kwPatterns[idx], i)
+		}
+	}
+
+	m.automaton = newKeywordAutomaton(keywords)
+
+	if companions, refs := companionPatterns(list); len(companions) > 0 {
+		m.companions = newPatternMatcher(companions)
+		m.companionRefs = refs
+	}
+	return m
+}
+
+// candidates retourne, dans l'ordre des patterns, ceux dont un mot-clé apparaît dans la ligne
+func (m *patternMatcher) candidates(line string, selected []bool) []bool {
+	for i := range selected {
+		selected[i] = false
+	}
+	for _, i := range m.always {
+		selected[i] = true
+	}
+
+	nodes := m.automaton.nodes
+	state := int32(0)
+	for i := 0; i < len(line); i++ {
+		state = nodes[state].next[lowerASCII(line[i])]
+		for _, kw := range nodes[state].out {
+			for _, p := range m.kwPatterns[kw] {
+				selected[p] = true
+			}
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,142 @@
+package scan
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// lineWindow est la taille de lecture d'une ligne: une ligne plus longue (bundle
+	// minifié, JSON sur une seule ligne) est scannée en plusieurs fenêtres
+	lineWindow = 64 * 1024
+	// lineWindowOverlap est le recouvrement entre deux fenêtres d'une même ligne: un
+	// secret plus court est toujours vu entier dans une fenêtre
+	lineWindowOverlap = 8 * 1024
+)
+
+// lineReader lit un contenu ligne par ligne, avec une mémoire bornée quelle que soit
+// la longueur des lignes. Une ligne longue est découpée en fenêtres d'au plus
+// lineWindowOverlap + lineWindow octets; chaque fenêtre reprend la fin de la précédente.
+type lineReader struct {
+	r      *bufio.Reader
+	buf    []byte
+	lineNo int
+	carry  int   // Octets de la fenêtre repris de la fenêtre précédente
+	offset int   // Position de la fenêtre dans la ligne
+	more   bool  // La ligne continue après cette fenêtre
+	failed error // Erreur de lecture, io.EOF en fin de contenu
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: bufio.NewReaderSize(r, lineWindow)}
+}
+
+// next lit la fenêtre suivante: la suite de la ligne en cours, ou la ligne suivante
+func (lr *lineReader) next() bool {
+	if lr.failed != nil {
+		return false
+	}
+
+	if lr.more {
+		// Reprendre la fin de la fenêtre précédente
+		lr.carry = min(lineWindowOverlap, len(lr.buf))
+		lr.offset += len(lr.buf) - lr.carry
+		lr.buf = append(lr.buf[:0], lr.buf[len(lr.buf)-lr.carry:]...)
+	} else {
+		lr.lineNo++
+		lr.carry, lr.offset = 0, 0
+		lr.buf = lr.buf[:0]
+	}
+
+	chunk, err := lr.r.ReadSlice('\n')
+	lr.buf = append(lr.buf, chunk...)
+	switch {
+	case err == nil:
+		lr.more = false
+		lr.buf = dropCR(lr.buf[:len(lr.buf)-1])
+	case errors.Is(err, bufio.ErrBufferFull):
+		lr.more = true
+	case err == io.EOF:
+		lr.more = false
+		lr.failed = err
+		lr.buf = dropCR(lr.buf)
+		// Dernière ligne sans retour à la ligne (rien à lire si elle est vide)
+		return len(chunk) > 0 || lr.carry > 0
+	default:
+		lr.failed = err
+		return false
+	}
+	return true
+}
+
+// dropCR retire le retour chariot d'une fin de ligne Windows
+func dropCR(b []byte) []byte {
+	if len(b) > 0 && b[len(b)-1] == '\r' {
+		return b[:len(b)-1]
+	}
+	return b
+}
+
+// text retourne la fenêtre courante
+func (lr *lineReader) text() string {
+	return string(lr.buf)
+}
+
+// line retourne le numéro de la ligne en cours
+func (lr *lineReader) line() int {
+	return lr.lineNo
+}
+
+// owns indique si une correspondance commençant à start dans la fenêtre lui revient:
+// tant que la ligne continue, les correspondances qui commencent dans la partie
+// reprise par la fenêtre suivante sont laissées à celle-ci, où elles sont entières
+func (lr *lineReader) owns(start int) bool {
+	return !lr.more || start < len(lr.buf)-lineWindowOverlap
+}
+
+// err retourne l'erreur de lecture (nil en fin de contenu)
+func (lr *lineReader) err() error {
+	if lr.failed == io.EOF {
+		return nil
+	}
+	return lr.failed
+}
+
+const (
+	// maxContextLength est la longueur au-delà de laquelle le contexte d'un secret est
+	// un extrait de la ligne autour du secret
+	maxContextLength = 512
+	// contextRadius est le nombre d'octets gardés de part et d'autre du secret dans l'extrait
+	contextRadius = 80
+)
+
+// contextSnippet retourne le contexte d'un secret trouvé en [start, end) dans une
+// ligne: la ligne entière si elle est courte, sinon un extrait autour du secret
+func contextSnippet(line string, start, end int) string {
+	if len(line) <= maxContextLength {
+		return strings.TrimSpace(line)
+	}
+
+	from := max(0, start-contextRadius)
+	to := min(len(line), end+contextRadius)
+	// Ne pas couper un caractère UTF-8
+	for from > 0 && !utf8.RuneStart(line[from]) {
+		from--
+	}
+	for to < len(line) && !utf8.RuneStart(line[to]) {
+		to++
+	}
+
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	b.WriteString(strings.TrimSpace(line[from:to]))
+	if to < len(line) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package scan
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestLineReaderWindows(t *testing.T) {
+	long := strings.Repeat("a", 3*lineWindow)
+	lr := newLineReader(strings.NewReader("court\r\n" + long + "\nfin"))
+
+	type window struct {
+		line, carry, offset int
+		more                bool
+	}
+	var got []window
+	var rebuilt strings.Builder
+	for lr.next() {
+		got = append(got, window{lr.line(), lr.carry, lr.offset, lr.more})
+		if lr.line() == 2 {
+			rebuilt.WriteString(lr.text()[lr.carry:])
+		}
+	}
+	if err := lr.err(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got[0] != (window{1, 0, 0, false}) || got[len(got)-1].line != 3 {
+		t.Errorf("fenêtres %+v", got)
+	}
+	for _, w := range got[1 : len(got)-1] {
+		if w.line != 2 {
+			t.Errorf("fenêtre %+v hors de la ligne longue", w)
+		}
+	}
+	if second := got[2]; second.carry != lineWindowOverlap || second.offset != lineWindow-lineWindowOverlap {
+		t.Errorf("deuxième fenêtre %+v, attendu carry %d et offset %d", second, lineWindowOverlap, lineWindow-lineWindowOverlap)
+	}
+	if rebuilt.String() != long {
+		t.Errorf("ligne reconstituée de %d octets, attendu %d", rebuilt.Len(), len(long))
+	}
+}
+
+// Un secret à cheval sur deux fenêtres, ou dans leur recouvrement, est rapporté une seule fois
+func TestLongLineWindowBoundaries(t *testing.T) {
+	var b strings.Builder
+	var keys []string
+	for i, pos := range []int{
+		lineWindow - lineWindowOverlap - 10, // Juste avant le recouvrement
+		lineWindow - 10,                     // À cheval sur la fin de la première fenêtre
+		2*lineWindow - lineWindowOverlap,    // Début du recouvrement suivant
+		2*lineWindow - 5,
+	} {
+		b.WriteString(strings.Repeat(" ", pos-b.Len()))
+		key := fmt.Sprintf("AKIAQ7ZKXW3JDN5T%04d", i)
+		keys = append(keys, key)
+		b.WriteString(key)
+	}
+	b.WriteString(strings.Repeat(" ", lineWindow))
+
+	secrets, err := ScanBytes("bundle.js", []byte(b.String()), DefaultScanOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := make(map[string]int)
+	for _, s := range secrets {
+		count[s.OriginalMatch]++
+		if s.Line != 1 || len(s.Context) > 2*contextRadius+len(s.OriginalMatch)+len("……") {
+			t.Errorf("secret %s: ligne %d, contexte de %d octets", s.OriginalMatch, s.Line, len(s.Context))
+		}
+	}
+	for _, key := range keys {
+		if count[key] != 1 {
+			t.Errorf("%s rapporté %d fois, attendu 1", key, count[key])
+		}
+	}
+}
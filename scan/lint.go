@@ -0,0 +1,311 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// Catégories de problèmes signalés par LintRules
+const (
+	LintDuplicate  = "duplicate"  // Deux règles ont la même regex ou détectent exactement les mêmes secrets
+	LintSubsumed   = "subsumed"   // Tous les secrets d'exemple d'une règle sont aussi détectés par une autre
+	LintUnanchored = "unanchored" // La regex ne contient aucun littéral obligatoire (préfixe, contexte)
+	LintNonSecret  = "non-secret" // La règle détecte une valeur courante qui n'est pas un secret
+)
+
+// minAnchorLength est la longueur minimale d'un littéral pour ancrer une regex
+const minAnchorLength = 3
+
+// nonSecretCorpus contient des lignes courantes qui ne sont pas des secrets:
+// empreintes hexadécimales, UUID, valeurs base64 avec padding. Les valeurs
+// utilisent les générateurs d'exemples des règles (voir patterns.ExpandExample).
+var nonSecretCorpus = []string{
+	"commit {{hex:40}}",
+	"Merge: {{hex:7}} {{hex:7}}",
+	"md5sum: {{hex:32}}",
+	`"etag": "{{hex:32}}"`,
+	"sha1 = {{hex:40}}",
+	"{{hex:64}}  goleaks_linux_amd64.tar.gz",
+	"checksum: sha256:{{hex:64}}",
+	"image: alpine@sha256:{{hex:64}}",
+	"sha512: {{hex:128}}",
+	"SHA1: {{upper_hex:40}}",
+	"SHA256: {{upper_hex:64}}",
+	`"id": "{{uuid:1}}"`,
+	"request_id={{uuid:2}}",
+	"UUID: {{upper_hex:8}}-{{upper_hex:4}}-{{upper_hex:4}}-{{upper_hex:4}}-{{upper_hex:12}}",
+	`"integrity": "sha512-{{base64:86}}=="`,
+	`"integrity": "sha1-{{base64:27}}="`,
+	"github.com/TALLHAMADOU/goleaks v1.0.0 h1:{{base64:43}}=",
+	"data:image/png;base64,{{base64:64}}==",
+	`<input type="hidden" name="__VIEWSTATE" value="{{base64:96}}=" />`,
+}
+
+// LintIssue décrit un problème détecté dans une règle par LintRules
+type LintIssue struct {
+	RuleID  string
+	Other   string // Règle en conflit (duplicate, subsumed) ou ligne du corpus détectée (non-secret)
+	Kind    string // LintDuplicate, LintSubsumed, LintUnanchored ou LintNonSecret
+	Message string
+}
+
+// Blocking indique si le problème doit faire échouer goleaks rules lint
+// (une regex sans littéral n'est qu'un avertissement)
+func (i LintIssue) Blocking() bool {
+	return i.Kind != LintUnanchored
+}
+
+// LintRules analyse un ensemble de règles (intégrées et personnalisées) et signale
+// les règles en double ou englobées par une autre, les regex sans littéral
+// d'ancrage et les règles qui détectent des valeurs courantes du corpus de
+// non-secrets. Le recouvrement est évalué sur les exemples positifs des règles:
+// une règle en englobe une autre si elle détecte les mêmes secrets sur tous ses exemples.
+func LintRules(list []patterns.Pattern) ([]LintIssue, error) {
+	var issues []LintIssue
+
+	// Secrets détectés par chaque règle sur les exemples de chaque règle
+	lints := make([]ruleLint, len(list))
+	for i, p := range list {
+		lints[i] = newRuleLint(p)
+	}
+	examples := make([][]exampleSecrets, len(list))
+	for i, p := range list {
+		for _, example := range p.Examples {
+			found, err := lints[i].find(example)
+			if err != nil {
+				return nil, err
+			}
+			examples[i] = append(examples[i], exampleSecrets{text: example, found: found})
+		}
+	}
+
+	for i, p := range list {
+		if !p.Fallback {
+			for j := i + 1; j < len(list); j++ {
+				q := list[j]
+				if q.Fallback {
+					continue
+				}
+				if p.Regex.String() == q.Regex.String() {
+					issues = append(issues, LintIssue{RuleID: q.ID, Other: p.ID, Kind: LintDuplicate,
+						Message: fmt.Sprintf("même regex que %s", p.ID)})
+					continue
+				}
+				pCoversQ, err := lints[i].covers(examples[j])
+				if err != nil {
+					return nil, err
+				}
+				qCoversP, err := lints[j].covers(examples[i])
+				if err != nil {
+					return nil, err
+				}
+				switch {
+				case pCoversQ && qCoversP:
+					issues = append(issues, LintIssue{RuleID: q.ID, Other: p.ID, Kind: LintDuplicate,
+						Message: fmt.Sprintf("détecte exactement les mêmes secrets d'exemple que %s", p.ID)})
+				case pCoversQ:
+					issues = append(issues, LintIssue{RuleID: q.ID, Other: p.ID, Kind: LintSubsumed,
+						Message: fmt.Sprintf("tous ses exemples sont aussi détectés par %s", p.ID)})
+				case qCoversP:
+					issues = append(issues, LintIssue{RuleID: p.ID, Other: q.ID, Kind: LintSubsumed,
+						Message: fmt.Sprintf("tous ses exemples sont aussi détectés par %s", q.ID)})
+				}
+			}
+		}
+
+		if !hasLiteralAnchor(p.Regex) {
+			issues = append(issues, LintIssue{RuleID: p.ID, Kind: LintUnanchored,
+				Message: fmt.Sprintf("la regex ne contient aucun littéral d'au moins %d caractères (préfixe ou contexte)", minAnchorLength)})
+		}
+
+		for _, line := range nonSecretCorpus {
+			text, err := patterns.ExpandExample(line)
+			if err != nil {
+				return nil, err
+			}
+			found, err := lints[i].find(text)
+			if err != nil {
+				return nil, err
+			}
+			if len(found) > 0 {
+				issues = append(issues, LintIssue{RuleID: p.ID, Other: line, Kind: LintNonSecret,
+					Message: fmt.Sprintf("détecte une valeur courante qui n'est pas un secret: %q", line)})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// ruleLint scanne des textes avec une seule règle, sans tenir compte de sa portée
+type ruleLint struct {
+	opts    ScanOptions
+	matcher *patternMatcher
+}
+
+func newRuleLint(p patterns.Pattern) ruleLint {
+	p.Paths, p.FileTypes, p.PathRegex = nil, nil, nil
+	p.Allowlist.Paths, p.Allowlist.PathRegex = nil, nil
+	p.Fallback = false
+	return ruleLint{opts: DefaultScanOptions(), matcher: newPatternMatcher([]patterns.Pattern{p})}
+}
+
+// find retourne les secrets détectés dans un texte, sous la forme "ligne:valeur"
+func (l ruleLint) find(text string) (map[string]bool, error) {
+	secrets, err := scanReader(context.Background(), strings.NewReader(text), "", l.opts, l.matcher)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(secrets))
+	for _, s := range secrets {
+		found[fmt.Sprintf("%d:%s", s.Line, s.OriginalMatch)] = true
+	}
+	return found, nil
+}
+
+// exampleSecrets associe un exemple positif aux secrets qu'y détecte sa propre règle
+type exampleSecrets struct {
+	text  string
+	found map[string]bool
+}
+
+// covers indique si la règle détecte tous les secrets des exemples d'une autre règle
+// (faux si l'autre règle n'a aucun exemple détecté)
+func (l ruleLint) covers(examples []exampleSecrets) (bool, error) {
+	checked := 0
+	for _, e := range examples {
+		if len(e.found) == 0 {
+			continue
+		}
+		found, err := l.find(e.text)
+		if err != nil {
+			return false, err
+		}
+		for key := range e.found {
+			if !found[key] {
+				return false, nil
+			}
+		}
+		checked++
+	}
+	return checked > 0, nil
+}
+
+// hasLiteralAnchor indique si toute correspondance de la regex contient un littéral
+// d'au moins minAnchorLength caractères (ex: "ghp_", "-----BEGIN", "api_key")
+func hasLiteralAnchor(re *regexp.Regexp) bool {
+	return anchorLength(re) >= minAnchorLength
+}
+
+// anchorLength retourne la longueur du plus long littéral présent dans toute correspondance de la regex
+func anchorLength(re *regexp.Regexp) int {
+	parsed, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		return 0
+	}
+	return literalRuns(parsed.Simplify()).longest()
+}
+
+// maxAnchorClass est la taille maximale d'une classe de caractères considérée comme
+// un littéral (ex: "gh[su]_", "api[_.-]key")
+const maxAnchorClass = 4
+
+// literalRun décrit les littéraux obligatoires d'une sous-expression: soit elle est
+// entièrement littérale (exact, de longueur n), soit elle commence par un littéral de
+// longueur prefix, finit par un littéral de longueur suffix et en contient un de longueur best
+type literalRun struct {
+	exact                bool
+	n                    int
+	prefix, suffix, best int
+}
+
+func (r literalRun) longest() int {
+	if r.exact {
+		return r.n
+	}
+	return max(r.best, r.prefix, r.suffix)
+}
+
+// literalRuns calcule les littéraux obligatoires d'une regex simplifiée
+func literalRuns(re *syntax.Regexp) literalRun {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return literalRun{exact: true, n: len(re.Rune)}
+	case syntax.OpCharClass:
+		size := 0
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			size += int(re.Rune[i+1]-re.Rune[i]) + 1
+		}
+		if size <= maxAnchorClass {
+			return literalRun{exact: true, n: 1}
+		}
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText,
+		syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+		return literalRun{exact: true}
+	case syntax.OpCapture:
+		return literalRuns(re.Sub[0])
+	case syntax.OpPlus, syntax.OpRepeat:
+		if re.Op == syntax.OpPlus || re.Min >= 1 {
+			sub := literalRuns(re.Sub[0])
+			n := sub.longest()
+			if sub.exact {
+				return literalRun{prefix: n, suffix: n, best: n}
+			}
+			return literalRun{prefix: sub.prefix, suffix: sub.suffix, best: n}
+		}
+	case syntax.OpConcat:
+		run := literalRun{exact: true}
+		for _, sub := range re.Sub {
+			run = concatRuns(run, literalRuns(sub))
+		}
+		return run
+	case syntax.OpAlternate:
+		run := literalRuns(re.Sub[0])
+		for _, sub := range re.Sub[1:] {
+			other := literalRuns(sub)
+			if run.exact && other.exact && run.n == other.n {
+				continue
+			}
+			run = literalRun{
+				prefix: min(run.edge(true), other.edge(true)),
+				suffix: min(run.edge(false), other.edge(false)),
+				best:   min(run.longest(), other.longest()),
+			}
+		}
+		return run
+	}
+	return literalRun{}
+}
+
+// edge retourne la longueur du littéral en tête (prefix) ou en fin (suffix)
+func (r literalRun) edge(prefix bool) int {
+	switch {
+	case r.exact:
+		return r.n
+	case prefix:
+		return r.prefix
+	default:
+		return r.suffix
+	}
+}
+
+// concatRuns combine les littéraux de deux sous-expressions consécutives
+func concatRuns(a, b literalRun) literalRun {
+	if a.exact && b.exact {
+		return literalRun{exact: true, n: a.n + b.n}
+	}
+	joined := a.edge(false) + b.edge(true)
+	run := literalRun{prefix: a.prefix, suffix: b.suffix, best: max(a.longest(), b.longest(), joined)}
+	if a.exact {
+		run.prefix = joined
+	}
+	if b.exact {
+		run.suffix = joined
+	}
+	return run
+}
@@ -0,0 +1,103 @@
+package scan
+
+import (
+	"sort"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// Résolution des correspondances de plusieurs règles sur une même position
+const (
+	OverlapSpecific = "specific" // Garder la règle la plus spécifique, les autres sont notées comme candidates
+	OverlapAll      = "all"      // Rapporter un secret par règle
+)
+
+// OverlapModes liste les modes de résolution des chevauchements
+var OverlapModes = []string{OverlapSpecific, OverlapAll}
+
+// IsValidOverlap indique si un mode de résolution des chevauchements est reconnu (vide = specific)
+func IsValidOverlap(mode string) bool {
+	return mode == "" || mode == OverlapSpecific || mode == OverlapAll
+}
+
+// Candidate est une autre règle ayant détecté le même secret, écartée au profit d'une règle plus spécifique
+type Candidate struct {
+	RuleID  string
+	Service string
+}
+
+// lineFinding est un secret trouvé sur la ligne courante, avant résolution des chevauchements
+type lineFinding struct {
+	secret     Secret
+	pattern    int // Index du pattern dans le matcher
+	start, end int // Position du secret dans la ligne
+}
+
+// resolveOverlaps ne garde, parmi des secrets d'une même ligne qui se chevauchent, que
+// celui de la règle la plus spécifique: littéral d'ancrage le plus long (préfixe ou
+// contexte), puis correspondance la plus longue, puis ordre des règles. Les règles
+// écartées sont notées comme candidates sur le secret gardé. L'ordre des secrets est conservé.
+func (m *patternMatcher) resolveOverlaps(findings []lineFinding, mode string) []lineFinding {
+	if mode == OverlapAll || len(findings) < 2 {
+		return findings
+	}
+
+	ranked := make([]int, len(findings))
+	for i := range ranked {
+		ranked[i] = i
+	}
+	sort.SliceStable(ranked, func(a, b int) bool {
+		fa, fb := findings[ranked[a]], findings[ranked[b]]
+		if sa, sb := m.specificity[fa.pattern], m.specificity[fb.pattern]; sa != sb {
+			return sa > sb
+		}
+		return fa.end-fa.start > fb.end-fb.start
+	})
+
+	kept := make([]bool, len(findings))
+	for _, i := range ranked {
+		f := findings[i]
+		winner := -1
+		for j := range findings {
+			if kept[j] && f.start < findings[j].end && findings[j].start < f.end {
+				winner = j
+				break
+			}
+		}
+		if winner < 0 {
+			kept[i] = true
+			continue
+		}
+		w := &findings[winner].secret
+		if f.secret.RuleID != w.RuleID && !hasCandidate(w.Candidates, f.secret.RuleID) {
+			w.Candidates = append(w.Candidates, Candidate{RuleID: f.secret.RuleID, Service: f.secret.Service})
+		}
+	}
+
+	resolved := findings[:0]
+	for i, f := range findings {
+		if kept[i] {
+			resolved = append(resolved, f)
+		}
+	}
+	return resolved
+}
+
+// hasCandidate indique si une règle figure déjà parmi les candidates d'un secret
+func hasCandidate(candidates []Candidate, ruleID string) bool {
+	for _, c := range candidates {
+		if c.RuleID == ruleID {
+			return true
+		}
+	}
+	return false
+}
+
+// patternSpecificity mesure la spécificité d'une règle pour la résolution des
+// chevauchements: longueur de son littéral d'ancrage, les règles génériques en dernier
+func patternSpecificity(p patterns.Pattern) int {
+	if p.Fallback {
+		return -1
+	}
+	return anchorLength(p.Regex)
+}
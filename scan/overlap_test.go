@@ -0,0 +1,72 @@
+package scan
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// overlapPatterns retourne deux règles qui détectent le même token: l'une ancrée sur
+// un préfixe, l'autre sans ancrage
+func overlapPatterns() []patterns.Pattern {
+	return []patterns.Pattern{
+		{ID: "loose", Service: "Loose", Regex: regexp.MustCompile(`\b[a-zA-Z0-9_]{40}\b`), Severity: patterns.SeverityMedium},
+		{ID: "vendor", Service: "Vendor", Regex: regexp.MustCompile(`\bvnd_[a-zA-Z0-9]{36}\b`), Severity: patterns.SeverityHigh},
+	}
+}
+
+func TestResolveOverlaps(t *testing.T) {
+	content := "TOKEN=vnd_Q7ZKXW3JDN5TBR2Mq8Lw3rTn6vBk2mZx8pYc\n"
+	tests := []struct {
+		mode       string
+		want       string
+		candidates string
+	}{
+		{"", "vendor", "loose"},
+		{OverlapSpecific, "vendor", "loose"},
+		{OverlapAll, "loose,vendor", ""},
+	}
+	for _, tt := range tests {
+		opts := DefaultScanOptions()
+		opts.Overlap = tt.mode
+		secrets, err := scanReader(context.Background(), strings.NewReader(content), "app.env", opts, newPatternMatcher(overlapPatterns()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ruleIDs(secrets); got != tt.want {
+			t.Errorf("mode %q: règles %s, attendu %s", tt.mode, got, tt.want)
+			continue
+		}
+		var candidates []string
+		for _, c := range secrets[0].Candidates {
+			candidates = append(candidates, c.RuleID)
+		}
+		if got := strings.Join(candidates, ","); got != tt.candidates {
+			t.Errorf("mode %q: candidates %s, attendu %s", tt.mode, got, tt.candidates)
+		}
+	}
+}
+
+// Des secrets distincts sur une même ligne ne se chevauchent pas et sont tous gardés
+func TestResolveOverlapsDisjoint(t *testing.T) {
+	m := newPatternMatcher(overlapPatterns())
+	findings := []lineFinding{
+		{secret: Secret{RuleID: "loose"}, pattern: 0, start: 0, end: 40},
+		{secret: Secret{RuleID: "vendor"}, pattern: 1, start: 41, end: 81},
+		{secret: Secret{RuleID: "loose"}, pattern: 0, start: 41, end: 81},
+	}
+	resolved := m.resolveOverlaps(findings, OverlapSpecific)
+	var ids []string
+	for _, f := range resolved {
+		ids = append(ids, f.secret.RuleID)
+	}
+	if got := strings.Join(ids, ","); got != "loose,vendor" {
+		t.Errorf("règles %s, attendu loose,vendor (ordre d'origine conservé)", got)
+	}
+	if len(resolved[0].secret.Candidates) != 0 || len(resolved[1].secret.Candidates) != 1 {
+		t.Errorf("candidates: %v, %v", resolved[0].secret.Candidates, resolved[1].secret.Candidates)
+	}
+}
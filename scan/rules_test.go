@@ -0,0 +1,67 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// Cas limites des règles intégrées les plus fréquentes: chaque ligne est détectée par
+// la règle attendue seulement (ou par aucune règle pour une quasi-correspondance)
+func TestBuiltinRulesNearMisses(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		// Airtable: préfixe pat, 14 caractères, point et 64 caractères hexadécimaux
+		{"AIRTABLE_TOKEN=pat{{alnum:14}}.{{hex:64}}", "airtable-pat"},
+		{`-H "Authorization: Bearer pat{{alnum:14}}.{{hex:64}}"`, "airtable-pat"},
+		{"AIRTABLE_TOKEN=pat{{alnum:13}}.{{hex:64}}", ""},
+		{"AIRTABLE_TOKEN=pat{{alnum:14}}.{{hex:63}}", ""},
+		{"AIRTABLE_TOKEN=pat{{alnum:14}}{{hex:64}}", ""},
+		{"patterns := compilePatterns(path, patch)", ""},
+
+		// Shopify: quatre préfixes, 32 caractères hexadécimaux
+		{"SHOPIFY_ACCESS_TOKEN=shpat_{{hex:32}}", "shopify-token"},
+		{"SHOPIFY_SHARED_SECRET=shpss_{{upper_hex:32}}", "shopify-token"},
+		{"SHOPIFY_ACCESS_TOKEN=shpxx_{{hex:32}}", ""},
+		{"SHOPIFY_ACCESS_TOKEN=shpat_{{hex:31}}", ""},
+		{"SHOPIFY_ACCESS_TOKEN=shpat_{{alpha:32}}", ""},
+
+		// Stripe: seules les clés live sont rapportées
+		{"STRIPE_KEY=rk_live_{{alnum:24}}", "stripe-restricted-key"},
+		{"STRIPE_KEY=rk_test_{{alnum:24}}", ""},
+		{"STRIPE_KEY=sk_test_{{alnum:24}}", ""},
+
+		// OpenAI, Anthropic et xAI partagent le préfixe sk-: une seule règle par clé
+		{"OPENAI_API_KEY=sk-{{alnum:48}}", "openai-api-key"},
+		{"OPENAI_API_KEY=sk-{{alnum:47}}", ""},
+		{"OPENAI_API_KEY=sk-{{alnum:49}}", ""},
+		{"ANTHROPIC_API_KEY=sk-ant-api03-{{base64url:93}}AA", "anthropic-api-key"},
+		{"ANTHROPIC_API_KEY=sk-ant-api03-{{base64url:93}}AB", ""},
+		{"ANTHROPIC_API_KEY=sk-ant-api02-{{base64url:93}}AA", ""},
+		{"XAI_API_KEY=sk-grok-{{alnum:93}}AA", "xai-api-key"},
+		{"XAI_API_KEY=sk-grok-{{alnum:92}}AA", ""},
+
+		// Hugging Face: 34 lettres après hf_
+		{"HF_TOKEN=hf_{{alpha:34}}", "huggingface-token"},
+		{"HF_TOKEN=hf_{{alpha:33}}1", ""},
+		{"HF_TOKEN=hf_{{alpha:35}}", ""},
+	}
+
+	// Fichier hors de la portée de la règle générique, qui rapporterait les quasi-correspondances
+	opts := DefaultScanOptions()
+	for _, tt := range tests {
+		line, err := patterns.ExpandExample(tt.line)
+		if err != nil {
+			t.Fatal(err)
+		}
+		secrets, err := ScanBytes("notes.txt", []byte(line+"\n"), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := ruleIDs(secrets); got != tt.want {
+			t.Errorf("%s: règles %q, attendu %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -1,13 +1,16 @@
 package scan
 
 import (
-	"bufio"
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"io/fs"
+	"io"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/TALLHAMADOU/goleaks/patterns"
 )
@@ -16,12 +19,28 @@ import (
 type Secret struct {
 	File          string
 	Line          int
+	EndLine       int // Dernière ligne du secret (différente de Line pour les blocs multi-lignes)
+	RuleID        string
 	Service       string
-	Match         string // Secret masqué pour affichage
-	OriginalMatch string // Secret original (non masqué) pour verify-light
-	Risk          string
-	Context       string // Ligne complète pour contexte
-	IsHighRisk    bool   // Pour verify-light
+	Description   string
+	Tags          []string
+	CWE           string
+	DocsURL       string
+	Match         string              // Secret masqué pour affichage
+	OriginalMatch string              // Secret original (non masqué) pour verify-light
+	Entropy       float64             // Entropie de Shannon du secret, en bits par caractère
+	Severity      patterns.Severity   // Sévérité (défaut de la règle, ajustée par les analyseurs, checksums et remplacements)
+	Confidence    patterns.Confidence // Confiance dans le fait que la correspondance soit un vrai secret
+	Risk          string              // Deprecated: utiliser Severity, dont Risk reprend la valeur finale
+	Context       string              // Ligne du secret, ou extrait autour du secret si la ligne est longue
+	IsHighRisk    bool                // Pour verify-light
+	VerifyURL     string              // Endpoint de vérification de la règle (verify-light)
+	VerifyHeader  string              // En-tête d'authentification de la vérification
+	Companions    []CompanionMatch    // Secrets compagnons d'une règle composite (ex: secret access key AWS)
+	JWT           *JWTClaims          // Claims décodés si le secret est un JWT (analyseur "jwt")
+	URL           *URLCredential      // URL contenant le secret (analyseur "url")
+	Checksum      string              // Statut du checksum hors ligne: "valid", "invalid" ou vide (pas de validateur)
+	Candidates    []Candidate         // Autres règles ayant détecté le même secret (résolution des chevauchements)
 }
 
 // ScanResult contient les résultats du scan
@@ -29,6 +48,30 @@ type ScanResult struct {
 	Secrets []Secret
 	Files   int
 	Errors  []string
+	Ruleset patterns.Ruleset // Packs et version des règles utilisées
+
+	// Scan interrompu (annulation, délai global dépassé): les résultats sont partiels
+	Incomplete       bool
+	IncompleteReason string // Cause de l'interruption (IncompleteCanceled ou IncompleteTimeout)
+}
+
+// Causes d'un scan incomplet
+const (
+	IncompleteCanceled = "canceled" // Contexte annulé (ex: Ctrl-C)
+	IncompleteTimeout  = "timeout"  // Délai global dépassé
+)
+
+// MarkIncomplete marque un résultat comme partiel si le contexte du scan est terminé
+func (r *ScanResult) MarkIncomplete(ctx context.Context) {
+	switch ctx.Err() {
+	case nil:
+		return
+	case context.DeadlineExceeded:
+		r.IncompleteReason = IncompleteTimeout
+	default:
+		r.IncompleteReason = IncompleteCanceled
+	}
+	r.Incomplete = true
 }
 
 // ScanOptions contient les options de scan
@@ -39,6 +82,22 @@ type ScanOptions struct {
 	IgnoreDirs     []string
 	IACSupport     bool
 	TextExtensions map[string]bool
+	Patterns       []patterns.Pattern // Patterns actifs (nil = patterns intégrés)
+	Rules          []string           // Identifiants des règles à exécuter (vide = toutes)
+	ExcludeRules   []string           // Identifiants des règles à ne pas exécuter
+	Tags           []string           // Ne garder que les règles portant l'un de ces tags (vide = toutes)
+	MinSeverity    patterns.Severity  // Sévérité minimale des secrets rapportés (vide = tous)
+	MinRisk        string             // Deprecated: utiliser MinSeverity (MinRisk s'applique si MinSeverity est vide)
+	Concurrency    int                // Nombre de fichiers scannés en parallèle (0 = GOMAXPROCS)
+	FileTimeout    time.Duration      // Durée maximale du scan d'un fichier (0 = illimitée)
+	Root           string             // Racine du scan: la portée des règles est évaluée sur le chemin relatif (vide = répertoire ou dépôt scanné)
+	IgnoreScope    bool               // Évaluer toutes les règles quel que soit le chemin (contenu sans nom de fichier, ex: entrée standard)
+
+	// Remplacements de sévérité par règle et par chemin (le dernier applicable l'emporte)
+	SeverityOverrides []patterns.SeverityOverride
+
+	DropInvalidChecksums bool   // Écarter les secrets dont le checksum est incorrect (par défaut leur sévérité est abaissée)
+	Overlap              string // Résolution des secrets détectés par plusieurs règles: "specific" (défaut) ou "all"
 }
 
 // DefaultScanOptions retourne les options par défaut
@@ -56,7 +115,8 @@ func DefaultScanOptions() ScanOptions {
 			".tf": true, ".tfvars": true, ".hcl": true,
 			".dockerfile": true, ".sh": true, ".bash": true, ".zsh": true,
 			".md": true, ".txt": true, ".conf": true, ".config": true,
-			".xml": true, ".html": true, ".css": true, ".scss": true,
+			".xml": true, ".html": true, ".css": true, ".scss": true, ".properties": true,
+			".pem": true, ".key": true, ".asc": true,
 		},
 	}
 }
@@ -89,6 +149,98 @@ func (opts ScanOptions) ShouldIgnore(path string) bool {
 	return false
 }
 
+// ActivePatterns retourne les patterns à utiliser pour le scan, après sélection
+// des règles par identifiant et par tag
+func (opts ScanOptions) ActivePatterns() []patterns.Pattern {
+	return opts.RuleFilter().Apply(opts.basePatterns())
+}
+
+// basePatterns retourne les patterns configurés, avant sélection
+func (opts ScanOptions) basePatterns() []patterns.Pattern {
+	if opts.Patterns != nil {
+		return opts.Patterns
+	}
+	return patterns.GetPatterns()
+}
+
+// RuleFilter retourne la sélection de règles demandée par les options
+func (opts ScanOptions) RuleFilter() patterns.RuleFilter {
+	return patterns.RuleFilter{Rules: opts.Rules, ExcludeRules: opts.ExcludeRules, Tags: opts.Tags}
+}
+
+// Validate vérifie la sélection de règles, la sévérité minimale, les remplacements de
+// sévérité, le nombre de threads, le délai par fichier et le mode de résolution des chevauchements
+func (opts ScanOptions) Validate() error {
+	if err := opts.RuleFilter().Validate(opts.basePatterns()); err != nil {
+		return err
+	}
+	if minimum := opts.minimumSeverity(); minimum != "" {
+		if _, err := patterns.ParseSeverity(string(minimum)); err != nil {
+			return fmt.Errorf("sévérité minimale: %v", err)
+		}
+	}
+	for i, override := range opts.SeverityOverrides {
+		if err := override.Validate(opts.basePatterns()); err != nil {
+			return fmt.Errorf("remplacement de sévérité #%d: %v", i+1, err)
+		}
+	}
+	if opts.Concurrency < 0 {
+		return fmt.Errorf("nombre de threads invalide: %d", opts.Concurrency)
+	}
+	if opts.FileTimeout < 0 {
+		return fmt.Errorf("délai par fichier invalide: %s", opts.FileTimeout)
+	}
+	if !IsValidOverlap(opts.Overlap) {
+		return fmt.Errorf("résolution des chevauchements invalide %q (attendu: %s)", opts.Overlap, strings.Join(OverlapModes, ", "))
+	}
+	if len(opts.ActivePatterns()) == 0 {
+		return fmt.Errorf("aucune règle active après sélection")
+	}
+	return nil
+}
+
+// minimumSeverity retourne la sévérité minimale demandée (MinSeverity, ou l'ancien MinRisk)
+func (opts ScanOptions) minimumSeverity() patterns.Severity {
+	if opts.MinSeverity != "" {
+		return opts.MinSeverity
+	}
+	return patterns.Severity(opts.MinRisk)
+}
+
+// meetsMinSeverity indique si un secret atteint la sévérité minimale demandée
+func (opts ScanOptions) meetsMinSeverity(secret Secret) bool {
+	minimum := opts.minimumSeverity()
+	return minimum == "" || secret.Severity.AtLeast(minimum)
+}
+
+// applySeverityOverrides applique aux secrets les remplacements de sévérité de leur règle
+// et de leur fichier; les chemins sont comparés relativement à la racine du scan, comme
+// la portée des règles
+func (opts ScanOptions) applySeverityOverrides(secrets []Secret) {
+	for i := range secrets {
+		path := opts.relativePath(secrets[i].File)
+		for _, override := range opts.SeverityOverrides {
+			if override.Matches(secrets[i].RuleID, path) {
+				secrets[i].Severity = override.Severity
+			}
+		}
+	}
+}
+
+// relativePath retourne le chemin d'un fichier relatif à la racine du scan, au format
+// "/", auquel sont comparés les chemins et les regex de chemins des règles (ex: les
+// regex ancrées "^config/" importées de gitleaks). Un fichier hors de la racine garde son chemin.
+func (opts ScanOptions) relativePath(filePath string) string {
+	if opts.Root == "" {
+		return filepath.ToSlash(filePath)
+	}
+	rel, err := filepath.Rel(opts.Root, filePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.ToSlash(filePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
 // IsTextFile vérifie si un fichier est un fichier texte scannable
 func (opts ScanOptions) IsTextFile(filename string) bool {
 	ext := strings.ToLower(filepath.Ext(filename))
@@ -112,165 +264,319 @@ func (opts ScanOptions) IsTextFile(filename string) bool {
 	return false
 }
 
-// CalculateEntropy calcule l'entropie Shannon d'une chaîne pour détecter les secrets aléatoires
+// CalculateEntropy calcule l'entropie Shannon d'une chaîne (bits par caractère) pour détecter les secrets aléatoires
 func CalculateEntropy(s string) float64 {
-	if len(s) == 0 {
-		return 0
-	}
-
-	freq := make(map[rune]int)
-	for _, char := range s {
-		freq[char]++
-	}
+	return patterns.ShannonEntropy(s)
+}
 
-	entropy := 0.0
-	length := float64(len(s))
-	for _, count := range freq {
-		p := float64(count) / length
-		if p > 0 {
-			entropy -= p * (p * 3.321928) // log2 via approximation
-		}
-	}
+// uuidPattern reconnaît un UUID (faux positif commun)
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 
-	return entropy
+// IsLikelySecret vérifie si une chaîne correspondant à un pattern est probablement un secret,
+// avec les seuils d'entropie par défaut.
+//
+// Deprecated: le contexte et le service sont ignorés (les vérifications propres à un
+// service sont portées par la regex de sa règle); utiliser IsLikelySecretWithThresholds.
+func IsLikelySecret(match string, context string, service string, smartMode bool) bool {
+	return IsLikelySecretWithThresholds(match, smartMode, patterns.DefaultEntropyThresholds)
 }
 
-// IsLikelySecret vérifie si une chaîne correspondant à un pattern est probablement un secret
-func IsLikelySecret(match string, context string, service string, smartMode bool) bool {
+// IsLikelySecretWithThresholds vérifie si une chaîne correspondant à un pattern est probablement
+// un secret. En mode smart, l'entropie normalisée doit atteindre le seuil de son jeu de caractères
+// (hex, base64, alphanumérique); les seuils non définis prennent les valeurs par défaut.
+func IsLikelySecretWithThresholds(match string, smartMode bool, thresholds patterns.EntropyThresholds) bool {
 	if !smartMode {
 		return true
 	}
 
-	// Vérifier l'entropie pour les patterns génériques
-	entropy := CalculateEntropy(match)
-
-	// Patterns avec entropie élevée sont probablement des secrets
-	if entropy > 4.0 && len(match) > 20 {
-		return true
-	}
-
 	// Vérifier si c'est un UUID (faux positif commun)
-	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
 	if uuidPattern.MatchString(strings.ToLower(match)) {
 		return false
 	}
 
-	// Vérifier si c'est un hash hexadécimal simple (faux positif)
-	hexPattern := regexp.MustCompile(`^[a-f0-9]{32,64}$`)
-	if hexPattern.MatchString(strings.ToLower(match)) && entropy < 3.5 {
-		return false
-	}
-
-	// Vérifier le contexte pour certains services
-	lowerContext := strings.ToLower(context)
-	if service == "Algolia" && !strings.Contains(lowerContext, "algolia") {
-		return false
-	}
-	if service == "Asana" && !strings.Contains(lowerContext, "asana") {
-		return false
-	}
-
-	return true
+	// Chaînes peu aléatoires (hash répétitif, valeur d'exemple, texte)
+	return thresholds.WithDefaults(patterns.DefaultEntropyThresholds).Allows(match)
 }
 
 // ScanFile scanne un fichier pour détecter les secrets
 func ScanFile(filePath string, opts ScanOptions) ([]Secret, error) {
+	return ScanFileContext(context.Background(), filePath, opts)
+}
+
+// ScanFileContext scanne un fichier en respectant l'annulation du contexte et
+// opts.FileTimeout. Si le scan est interrompu, les secrets trouvés jusque-là sont
+// retournés avec l'erreur du contexte (ou ErrFileTimeout).
+func ScanFileContext(ctx context.Context, filePath string, opts ScanOptions) ([]Secret, error) {
+	if opts.Root == "" {
+		opts.Root = filepath.Dir(filePath)
+	}
+	return scanFile(ctx, filePath, opts, newPatternMatcher(opts.ActivePatterns()))
+}
+
+// ErrFileTimeout signale qu'un fichier n'a pas pu être scanné dans le délai opts.FileTimeout
+var ErrFileTimeout = errors.New("délai par fichier dépassé")
+
+// scanFile scanne un fichier avec un préfiltre par mots-clés déjà construit
+func scanFile(ctx context.Context, filePath string, opts ScanOptions, matcher *patternMatcher) ([]Secret, error) {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	var secrets []Secret
-	scanner := bufio.NewScanner(file)
-	lineNum := 0
-
-	for scanner.Scan() {
-		lineNum++
-		line := scanner.Text()
-
-		// Vérifier chaque pattern
-		for _, pattern := range patterns.GetPatterns() {
-			matches := pattern.Regex.FindAllString(line, -1)
-			for _, match := range matches {
-				if IsLikelySecret(match, line, pattern.Service, opts.SmartMode) {
-					secrets = append(secrets, Secret{
-						File:          filePath,
-						Line:          lineNum,
-						Service:       pattern.Service,
-						Match:         maskSecret(match),
-						OriginalMatch: match, // Secret original pour verify-light
-						Risk:          pattern.Risk,
-						Context:       strings.TrimSpace(line),
-						IsHighRisk:    pattern.IsHighRisk,
-					})
-				}
-			}
-		}
-	}
+	return scanContent(ctx, file, filePath, opts, matcher)
+}
 
-	if err := scanner.Err(); err != nil {
-		return nil, err
-	}
+// ScanReader scanne un contenu quelconque (blob Git, corps de requête HTTP, entrée
+// standard) sans fichier sur disque. name remplace le chemin du fichier: il est
+// rapporté dans Secret.File et détermine la portée des règles (chemins, extensions)
+// et les remplacements de sévérité. Pour un contenu sans nom de fichier, opts.IgnoreScope
+// applique toutes les règles. Si la lecture échoue en cours de route, les secrets trouvés
+// avant l'erreur sont retournés avec elle.
+func ScanReader(name string, r io.Reader, opts ScanOptions) ([]Secret, error) {
+	return ScanReaderContext(context.Background(), name, r, opts)
+}
 
-	return secrets, nil
+// ScanReaderContext scanne un contenu en respectant l'annulation du contexte et
+// opts.FileTimeout, comme ScanFileContext
+func ScanReaderContext(ctx context.Context, name string, r io.Reader, opts ScanOptions) ([]Secret, error) {
+	return scanContent(ctx, r, name, opts, newPatternMatcher(opts.ActivePatterns()))
 }
 
-// maskSecret masque partiellement un secret pour l'affichage
-func maskSecret(secret string) string {
-	if len(secret) <= 8 {
-		return "***"
+// ScanBytes scanne un contenu en mémoire, rapporté sous le nom name (voir ScanReader)
+func ScanBytes(name string, data []byte, opts ScanOptions) ([]Secret, error) {
+	return ScanReader(name, bytes.NewReader(data), opts)
+}
+
+// scanContent scanne un contenu dans le délai opts.FileTimeout
+func scanContent(ctx context.Context, r io.Reader, name string, opts ScanOptions, matcher *patternMatcher) ([]Secret, error) {
+	if opts.FileTimeout <= 0 {
+		return scanReader(ctx, r, name, opts, matcher)
 	}
-	return secret[:4] + "..." + secret[len(secret)-4:]
+	fileCtx, cancel := context.WithTimeout(ctx, opts.FileTimeout)
+	defer cancel()
+	secrets, err := scanReader(fileCtx, r, name, opts, matcher)
+	if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("%w (%s)", ErrFileTimeout, opts.FileTimeout)
+	}
+	return secrets, err
 }
 
-// ScanDirectory scanne récursivement un répertoire
-func ScanDirectory(rootPath string, opts ScanOptions) (*ScanResult, error) {
-	result := &ScanResult{
-		Secrets: []Secret{},
-		Files:   0,
-		Errors:  []string{},
+// scanReader scanne un contenu ligne par ligne (par fenêtres pour les lignes très longues,
+// voir lineReader); filePath sert au rapport et à la portée des règles.
+// Le contexte est vérifié à chaque ligne: s'il est terminé, les secrets déjà trouvés sont
+// retournés avec son erreur, de même en cas d'erreur de lecture.
+func scanReader(ctx context.Context, r io.Reader, filePath string, opts ScanOptions, matcher *patternMatcher) ([]Secret, error) {
+	var secrets []Secret
+	var blocks []*openBlock
+	composites := newCompositeTracker(matcher)
+	selected := make([]bool, len(matcher.patterns))
+
+	// Règles applicables à ce fichier (chemins, types de fichiers, allowlist de chemins)
+	enabled := make([]bool, len(matcher.patterns))
+	rulePath := opts.relativePath(filePath)
+	for i, pattern := range matcher.patterns {
+		enabled[i] = opts.IgnoreScope || pattern.AppliesTo(rulePath)
 	}
+	var spans [][2]int      // Positions des secrets trouvés sur la ligne courante par les règles spécifiques
+	var found []lineFinding // Secrets de la ligne courante, avant résolution des chevauchements
+	var reported [][2]int   // Positions dans la ligne des secrets rapportés par la fenêtre précédente (lignes longues)
+	var windowSpans [][2]int
+	lines := newLineReader(r)
+
+	for lines.next() {
+		if ctx.Err() != nil {
+			break
+		}
+		lineNum := lines.line()
+		line := lines.text()
+		if lines.carry == 0 {
+			reported = reported[:0]
+		}
 
-	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("Erreur accès %s: %v", path, err))
-			return nil
+		// Poursuivre les blocs multi-lignes en cours (clés privées PEM, OpenSSH, PGP),
+		// sans relire la partie reprise de la fenêtre précédente
+		fresh := line[lines.carry:]
+		remaining := blocks[:0]
+		for _, b := range blocks {
+			pattern := matcher.patterns[b.pattern]
+			if loc := pattern.EndRegex.FindStringIndex(fresh); loc != nil {
+				b.body.WriteString(blockBody(fresh[:loc[0]]))
+				if body := b.body.String(); len(body) >= minBlockBody {
+					secrets = append(secrets, newBlockSecret(filePath, b.startLine, lineNum, pattern, b.header, body, b.context))
+				}
+				continue
+			}
+			if lines.carry == 0 {
+				b.lines++ // Nouvelle ligne (et non suite d'une ligne longue)
+			}
+			if b.lines > maxBlockLines || b.body.Len() > maxBlockSize {
+				continue // Fin de bloc introuvable, abandon
+			}
+			b.body.WriteString(blockBody(fresh))
+			remaining = append(remaining, b)
 		}
+		blocks = remaining
+
+		// Collecter les compagnons des règles composites
+		composites.scanLine(line, lineNum, lines.offset)
+
+		// Évaluer seulement les patterns dont un mot-clé apparaît dans la ligne
+		matcher.candidates(line, selected)
+		spans = spans[:0]
+		found = found[:0]
+		for _, i := range matcher.order {
+			if !selected[i] || !enabled[i] {
+				continue
+			}
+			pattern := matcher.patterns[i]
 
-		// Ignorer les dossiers
-		if d.IsDir() {
-			if opts.ShouldIgnore(path) {
-				return filepath.SkipDir
+			if pattern.EndRegex != nil {
+				loc := pattern.Regex.FindStringIndex(line)
+				if loc == nil || !lines.owns(loc[0]) || isBlockOpen(blocks, i) {
+					continue
+				}
+				header := line[loc[0]:loc[1]]
+				snippet := contextSnippet(line, loc[0], loc[1])
+				// Bloc complet sur une seule ligne (clé échappée en JSON/YAML)
+				if body, ok := inlineBlock(pattern, line, loc[1]); ok {
+					if len(body) >= minBlockBody {
+						secrets = append(secrets, newBlockSecret(filePath, lineNum, lineNum, pattern, header, body, snippet))
+					}
+					continue
+				}
+				b := &openBlock{pattern: i, startLine: lineNum, header: header, context: snippet}
+				b.body.WriteString(blockBody(line[loc[1]:]))
+				blocks = append(blocks, b)
+				continue
+			}
+
+			for _, m := range pattern.FindSecrets(line) {
+				if pattern.Fallback && overlaps(spans, m) {
+					continue // Déjà rapporté par une règle spécifique
+				}
+				if !lines.owns(m.Start) || overlaps(reported, patterns.Match{Start: lines.offset + m.Start, End: lines.offset + m.End}) {
+					continue // Rapporté par une autre fenêtre de la ligne
+				}
+				match := m.Secret
+				if !IsLikelySecretWithThresholds(match, opts.SmartMode, pattern.EntropyThresholds) {
+					continue
+				}
+				secret := newSecret(filePath, lineNum, pattern, match, contextSnippet(line, m.Start, m.End))
+				if analyze, ok := analyzers[pattern.Analyzer]; ok && !analyze(&secret, m) {
+					continue // Écarté par l'analyseur (ex: URL invalide)
+				}
+				if pattern.Validator != "" && !checkChecksum(&secret, pattern.Validator, opts.DropInvalidChecksums) {
+					continue
+				}
+				spans = append(spans, [2]int{m.Start, m.End})
+				found = append(found, lineFinding{secret: secret, pattern: i, start: m.Start, end: m.End})
 			}
-			return nil
 		}
 
-		// Vérifier si le fichier doit être scanné
-		if opts.ShouldIgnore(path) {
-			return nil
+		// Une seule règle par secret: la plus spécifique (sauf en mode "all")
+		windowSpans = windowSpans[:0]
+		for _, f := range matcher.resolveOverlaps(found, opts.Overlap) {
+			if len(matcher.patterns[f.pattern].Companions) > 0 {
+				composites.track(len(secrets), f.pattern)
+			}
+			composites.locate(len(secrets), lineNum, lines.offset+f.start, lines.offset+f.end)
+			secrets = append(secrets, f.secret)
+			windowSpans = append(windowSpans, [2]int{lines.offset + f.start, lines.offset + f.end})
 		}
+		reported, windowSpans = windowSpans, reported
+	}
+
+	// Une erreur de lecture, comme une interruption, n'écarte pas les secrets déjà trouvés
+	scanErr := lines.err()
+	if scanErr == nil {
+		scanErr = ctx.Err()
+	}
 
-		if !opts.IsTextFile(path) {
-			return nil
+	// La sévérité minimale s'applique à la sévérité finale (ajustée par les analyseurs,
+	// les checksums puis les remplacements de la configuration)
+	resolved := composites.resolve(secrets)
+	opts.applySeverityOverrides(resolved)
+	for i := range resolved {
+		resolved[i].Risk = string(resolved[i].Severity)
+	}
+	if opts.minimumSeverity() == "" {
+		return resolved, scanErr
+	}
+	kept := resolved[:0]
+	for _, secret := range resolved {
+		if opts.meetsMinSeverity(secret) {
+			kept = append(kept, secret)
 		}
+	}
+	return kept, scanErr
+}
 
-		result.Files++
-		secrets, err := ScanFile(path, opts)
-		if err != nil {
-			result.Errors = append(result.Errors, fmt.Sprintf("Erreur scan %s: %v", path, err))
-			return nil
+// overlaps indique si une correspondance chevauche l'une des positions déjà rapportées
+func overlaps(spans [][2]int, m patterns.Match) bool {
+	for _, s := range spans {
+		if m.Start < s[1] && s[0] < m.End {
+			return true
 		}
+	}
+	return false
+}
 
-		result.Secrets = append(result.Secrets, secrets...)
-		return nil
-	})
+// analyzers associe les noms d'analyseurs (Pattern.Analyzer) à leur implémentation.
+// Un analyseur complète le secret et retourne false pour l'écarter.
+var analyzers = map[string]func(*Secret, patterns.Match) bool{
+	patterns.AnalyzerJWT: analyzeJWT,
+	patterns.AnalyzerURL: analyzeURL,
+}
 
-	if err != nil {
-		return nil, err
+// newSecret construit un secret détecté par un pattern sur une ligne
+func newSecret(filePath string, lineNum int, pattern patterns.Pattern, match string, context string) Secret {
+	return Secret{
+		File:          filePath,
+		Line:          lineNum,
+		EndLine:       lineNum,
+		RuleID:        pattern.ID,
+		Service:       pattern.Service,
+		Description:   pattern.Description,
+		Tags:          pattern.Tags,
+		CWE:           pattern.CWE,
+		DocsURL:       pattern.DocsURL,
+		Match:         maskSecret(match),
+		OriginalMatch: match, // Secret original pour verify-light
+		Entropy:       patterns.ShannonEntropy(match),
+		Severity:      pattern.Severity,
+		Confidence:    pattern.Confidence,
+		Context:       context,
+		IsHighRisk:    pattern.IsHighRisk,
+		VerifyURL:     pattern.VerifyURL,
+		VerifyHeader:  pattern.VerifyHeader,
 	}
+}
+
+// maskSecret masque partiellement un secret pour l'affichage
+func maskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return "***"
+	}
+	return secret[:4] + "..." + secret[len(secret)-4:]
+}
+
+// ScanDirectory scanne récursivement un répertoire. Les fichiers sont scannés en
+// parallèle (voir ScanOptions.Concurrency); les secrets et les erreurs sont triés
+// par chemin puis par ligne, pour un résultat identique d'un scan à l'autre.
+func ScanDirectory(rootPath string, opts ScanOptions) (*ScanResult, error) {
+	return ScanDirectoryContext(context.Background(), rootPath, opts)
+}
 
-	return result, nil
+// ScanDirectoryContext scanne récursivement un répertoire en respectant l'annulation
+// du contexte. Si le scan est interrompu, le résultat partiel est retourné, marqué
+// Incomplete, avec l'erreur du contexte. Un fichier dont le scan dépasse
+// opts.FileTimeout est signalé dans Errors sans interrompre le scan. Les secrets sont
+// rassemblés en mémoire: StreamDirectory les transmet au fur et à mesure.
+func ScanDirectoryContext(ctx context.Context, rootPath string, opts ScanOptions) (*ScanResult, error) {
+	var c collector
+	summary, err := StreamDirectory(ctx, rootPath, opts, c.finding, c.error)
+	return c.result(ctx, summary, err)
 }
 
 // VerifySecretLight est maintenant dans verify.go
@@ -0,0 +1,166 @@
+package scan
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+)
+
+// FindingHandler reçoit chaque secret dès que le scan de son fichier est terminé
+type FindingHandler func(Secret)
+
+// ErrorHandler reçoit chaque erreur de fichier (accès, lecture, délai); le scan continue
+type ErrorHandler func(*FileError)
+
+// FileError est une erreur rencontrée sur un fichier pendant le scan
+type FileError struct {
+	Op   string // "accès" (parcours du répertoire) ou "scan" (lecture, délai par fichier)
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("Erreur %s %s: %v", e.Op, e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// StreamSummary résume un scan en continu: les secrets et les erreurs ont été
+// transmis aux handlers
+type StreamSummary struct {
+	Files int // Fichiers scannés (fichiers avec secrets pour un scan Git diff)
+}
+
+// StreamDirectory scanne récursivement un répertoire et transmet les secrets et les
+// erreurs aux handlers (nil = ignorés) au fur et à mesure, fichier par fichier, sans
+// les conserver: la mémoire utilisée ne dépend pas du nombre de secrets. Les handlers
+// sont appelés depuis une seule goroutine, dans l'ordre de fin de scan des fichiers
+// (non déterministe avec plusieurs workers); un handler lent ralentit le scan.
+// Si le contexte est terminé, le scan s'arrête et son erreur est retournée.
+func StreamDirectory(ctx context.Context, rootPath string, opts ScanOptions, onFinding FindingHandler, onError ErrorHandler) (StreamSummary, error) {
+	if opts.Root == "" {
+		opts.Root = rootPath
+	}
+	matcher := newPatternMatcher(opts.ActivePatterns())
+	pool := newFilePool(ctx, opts, matcher, onFinding, onError)
+
+	err := filepath.WalkDir(rootPath, func(path string, d fs.DirEntry, err error) error {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			pool.fail(&FileError{Op: "accès", Path: path, Err: err})
+			return nil
+		}
+
+		// Ignorer les dossiers
+		if d.IsDir() {
+			if opts.ShouldIgnore(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Vérifier si le fichier doit être scanné
+		if opts.ShouldIgnore(path) {
+			return nil
+		}
+
+		if !opts.IsTextFile(path) {
+			return nil
+		}
+
+		pool.scan(path)
+		return nil
+	})
+
+	summary := StreamSummary{Files: pool.wait()}
+	if ctx.Err() != nil {
+		return summary, ctx.Err()
+	}
+	return summary, err
+}
+
+// ScanDirectoryStream lance StreamDirectory en arrière-plan et transmet les secrets
+// et les erreurs de fichiers (*FileError) par des canaux. Les deux canaux doivent être
+// lus jusqu'à leur fermeture (ex: select sur les deux); le canal d'erreurs reçoit en
+// dernier l'erreur du scan s'il a échoué ou a été interrompu. Une fois le contexte
+// terminé, une valeur n'est transmise que si un lecteur l'attend: les canaux sont
+// fermés même si l'appelant a cessé de les lire.
+func ScanDirectoryStream(ctx context.Context, rootPath string, opts ScanOptions) (<-chan Secret, <-chan error) {
+	findings := make(chan Secret)
+	errs := make(chan error)
+	go func() {
+		defer close(errs)
+		defer close(findings)
+		_, err := StreamDirectory(ctx, rootPath, opts,
+			func(s Secret) { send(ctx, findings, s) },
+			func(e *FileError) { send[error](ctx, errs, e) })
+		if err != nil {
+			send(ctx, errs, err)
+		}
+	}()
+	return findings, errs
+}
+
+// send transmet une valeur sur un canal, sauf si le contexte se termine avant. Un
+// lecteur déjà en attente reçoit la valeur même si le contexte est terminé.
+func send[T any](ctx context.Context, ch chan<- T, v T) {
+	select {
+	case ch <- v:
+		return
+	default:
+	}
+	select {
+	case ch <- v:
+	case <-ctx.Done():
+	}
+}
+
+// collector rassemble les secrets et les erreurs d'un scan en continu dans un ScanResult
+type collector struct {
+	secrets []Secret
+	errors  []*FileError
+}
+
+func (c *collector) finding(s Secret) {
+	c.secrets = append(c.secrets, s)
+}
+
+func (c *collector) error(e *FileError) {
+	c.errors = append(c.errors, e)
+}
+
+// result construit le résultat trié par chemin puis par ligne, pour un résultat identique
+// d'un scan à l'autre. Un scan interrompu retourne les résultats partiels avec l'erreur du contexte.
+func (c *collector) result(ctx context.Context, summary StreamSummary, err error) (*ScanResult, error) {
+	if err != nil && ctx.Err() == nil {
+		return nil, err
+	}
+
+	result := &ScanResult{
+		Secrets: c.secrets,
+		Files:   summary.Files,
+		Errors:  make([]string, 0, len(c.errors)),
+	}
+	if result.Secrets == nil {
+		result.Secrets = []Secret{}
+	}
+	SortSecrets(result.Secrets)
+	sort.SliceStable(c.errors, func(i, j int) bool {
+		return c.errors[i].Path < c.errors[j].Path
+	})
+	for _, e := range c.errors {
+		result.Errors = append(result.Errors, e.Error())
+	}
+
+	if ctx.Err() != nil {
+		result.MarkIncomplete(ctx)
+		return result, ctx.Err()
+	}
+	return result, nil
+}
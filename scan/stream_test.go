@@ -0,0 +1,130 @@
+package scan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// secretTree crée un répertoire de n fichiers contenant chacun un secret
+func secretTree(t *testing.T, n int) string {
+	t.Helper()
+	root := t.TempDir()
+	for i := 0; i < n; i++ {
+		content := fmt.Sprintf("AWS_ACCESS_KEY_ID=AKIAQ7ZKXW3JDN5T%04d\n", i)
+		if err := os.WriteFile(filepath.Join(root, fmt.Sprintf("app%03d.env", i)), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestStreamDirectoryMatchesScanDirectory(t *testing.T) {
+	root := secretTree(t, 50)
+	opts := DefaultScanOptions()
+
+	var streamed []Secret
+	summary, err := StreamDirectory(context.Background(), root, opts, func(s Secret) {
+		streamed = append(streamed, s)
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := ScanDirectory(root, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Files != 50 || result.Files != 50 {
+		t.Errorf("%d et %d fichiers scannés, attendu 50", summary.Files, result.Files)
+	}
+	if len(streamed) != 50 || len(result.Secrets) != 50 {
+		t.Errorf("%d secrets transmis et %d rassemblés, attendu 50", len(streamed), len(result.Secrets))
+	}
+}
+
+// Un scan interrompu retourne les résultats partiels, marqués incomplets
+func TestScanDirectoryContextCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err := ScanDirectoryContext(ctx, secretTree(t, 5), DefaultScanOptions())
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("erreur %v, attendu context.Canceled", err)
+	}
+	if result == nil || !result.Incomplete || result.IncompleteReason != IncompleteCanceled {
+		t.Errorf("résultat %+v, attendu incomplet (canceled)", result)
+	}
+}
+
+// Un délai global dépassé marque le résultat incomplet pour cause de délai
+func TestScanDirectoryContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+	result, err := ScanDirectoryContext(ctx, secretTree(t, 5), DefaultScanOptions())
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("erreur %v, attendu context.DeadlineExceeded", err)
+	}
+	if result == nil || !result.Incomplete || result.IncompleteReason != IncompleteTimeout {
+		t.Errorf("résultat %+v, attendu incomplet (timeout)", result)
+	}
+}
+
+// Un fichier qui dépasse son délai est signalé en erreur sans interrompre le scan
+func TestFileTimeout(t *testing.T) {
+	opts := DefaultScanOptions()
+	opts.FileTimeout = time.Nanosecond
+	result, err := ScanDirectory(secretTree(t, 3), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Incomplete || len(result.Errors) != 3 {
+		t.Fatalf("incomplet=%v, %d erreurs, attendu 3 erreurs de délai", result.Incomplete, len(result.Errors))
+	}
+	for _, e := range result.Errors {
+		if !strings.Contains(e, ErrFileTimeout.Error()) {
+			t.Errorf("erreur %q, attendu %q", e, ErrFileTimeout)
+		}
+	}
+
+	_, err = ScanReader("app.env", strings.NewReader(readerContent), opts)
+	if !errors.Is(err, ErrFileTimeout) {
+		t.Errorf("ScanReader: erreur %v, attendu ErrFileTimeout", err)
+	}
+}
+
+// Annuler le scan sans plus lire les secrets ferme les canaux: la goroutine du scan
+// ne reste pas bloquée sur un envoi
+func TestScanDirectoryStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	opts := DefaultScanOptions()
+	opts.Concurrency = 1
+	findings, errs := ScanDirectoryStream(ctx, secretTree(t, 200), opts)
+
+	if _, ok := <-findings; !ok {
+		t.Fatal("aucun secret transmis")
+	}
+	cancel()
+
+	// Seul le canal d'erreurs est lu: il doit être fermé après l'erreur du contexte
+	var last error
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case err, ok := <-errs:
+			if !ok {
+				if !errors.Is(last, context.Canceled) {
+					t.Errorf("dernière erreur %v, attendu context.Canceled", last)
+				}
+				return
+			}
+			last = err
+		case <-timeout:
+			t.Fatal("le scan ne s'est pas arrêté après l'annulation")
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package scan
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
+)
+
+// URLCredential décrit l'URL dans laquelle un identifiant a été trouvé
+type URLCredential struct {
+	Scheme    string
+	Host      string
+	Username  string
+	Parameter string // Paramètre de la query string contenant le token (vide pour un mot de passe)
+}
+
+// analyzeURL valide avec net/url l'URL contenant le secret (mot de passe de l'userinfo
+// ou valeur d'un paramètre), renseigne son schéma, son hôte et son utilisateur, et
+// masque uniquement le secret dans l'URL affichée
+func analyzeURL(secret *Secret, m patterns.Match) bool {
+	u, err := url.Parse(m.Full)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return false
+	}
+
+	info := &URLCredential{
+		Scheme: strings.ToLower(u.Scheme),
+		Host:   u.Host,
+	}
+	if u.User != nil {
+		info.Username = u.User.Username()
+	}
+
+	// Secret dans la query string: le paramètre est le nom qui précède "=" juste avant le secret
+	offset := m.Start - m.FullStart
+	if query := strings.IndexByte(m.Full, '?'); query >= 0 && offset > query {
+		prefix := strings.TrimSuffix(m.Full[:offset], "=")
+		info.Parameter = prefix[strings.LastIndexAny(prefix, "?&")+1:]
+	} else if password, ok := u.User.Password(); !ok || password == "" {
+		return false
+	}
+
+	secret.URL = info
+	secret.Match = m.Full[:offset] + maskSecret(m.Secret) + m.Full[offset+len(m.Secret):]
+	return true
+}
@@ -1,10 +1,11 @@
 package scan
 
 import (
-	"fmt"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/TALLHAMADOU/goleaks/patterns"
 )
 
 // VerifySecretLight effectue une vérification légère d'un secret (HEAD request minimale)
@@ -19,8 +20,8 @@ func VerifySecretLight(secret Secret) bool {
 		Timeout: 2 * time.Second,
 	}
 
-	// Construire l'URL de vérification selon le service
-	url := getVerificationURL(secret.Service, secret.OriginalMatch)
+	// URL de vérification définie par la règle
+	url := secret.VerifyURL
 	if url == "" {
 		return true // Pas d'URL de vérification, considérer comme valide
 	}
@@ -33,7 +34,13 @@ func VerifySecretLight(secret Secret) bool {
 
 	// User-agent personnalisé
 	req.Header.Set("User-Agent", "SecretHunter/1.0")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", secret.OriginalMatch))
+	header := secret.VerifyHeader
+	if header == "" {
+		header = patterns.DefaultVerifyHeader
+	}
+	if name, value, ok := strings.Cut(header, ":"); ok {
+		req.Header.Set(strings.TrimSpace(name), strings.TrimSpace(strings.ReplaceAll(value, "{secret}", secret.OriginalMatch)))
+	}
 
 	// Effectuer la requête
 	resp, err := client.Do(req)
@@ -54,30 +61,3 @@ func VerifySecretLight(secret Secret) bool {
 	// Autres codes = probablement invalide
 	return false
 }
-
-// getVerificationURL retourne l'URL de vérification pour un service donné
-func getVerificationURL(service string, _ string) string {
-	service = strings.ToLower(service)
-
-	switch service {
-	case "openai":
-		return "https://api.openai.com/v1/models"
-	case "github pat":
-		return "https://api.github.com/user"
-	case "aws access key":
-		return "https://sts.amazonaws.com/"
-	case "stripe":
-		return "https://api.stripe.com/v1/charges"
-	case "azure ad":
-		return "https://graph.microsoft.com/v1.0/me"
-	case "alibaba":
-		return "https://ecs.aliyuncs.com/"
-	case "cloudflare":
-		return "https://api.cloudflare.com/client/v4/user/tokens/verify"
-	case "grok xai", "anthropic":
-		// Pas d'URL de vérification publique disponible
-		return ""
-	default:
-		return "" // Pas de vérification pour les autres services
-	}
-}
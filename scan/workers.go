@@ -0,0 +1,105 @@
+package scan
+
+import (
+	"context"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// fileResult est le résultat du scan d'un fichier par un worker
+type fileResult struct {
+	secrets []Secret
+	err     *FileError // Erreur d'accès ou de lecture, nil si le scan a réussi
+	scanned bool       // Le fichier a été ouvert et scanné
+}
+
+// filePool répartit les fichiers trouvés par le parcours du répertoire entre un
+// nombre borné de workers et transmet leurs résultats aux handlers
+type filePool struct {
+	paths   chan string
+	results chan fileResult
+	workers sync.WaitGroup
+	done    chan struct{}
+	files   int // Fichiers scannés
+}
+
+// newFilePool démarre les workers (opts.Concurrency, ou GOMAXPROCS) et le collecteur,
+// seule goroutine à appeler les handlers. Une fois le contexte terminé, les fichiers
+// restants ne sont plus scannés.
+func newFilePool(ctx context.Context, opts ScanOptions, matcher *patternMatcher, onFinding FindingHandler, onError ErrorHandler) *filePool {
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	p := &filePool{
+		paths:   make(chan string, workers),
+		results: make(chan fileResult, workers),
+		done:    make(chan struct{}),
+	}
+	for i := 0; i < workers; i++ {
+		p.workers.Add(1)
+		go func() {
+			defer p.workers.Done()
+			for path := range p.paths {
+				if ctx.Err() != nil {
+					continue
+				}
+				secrets, err := scanFile(ctx, path, opts, matcher)
+				r := fileResult{secrets: secrets, scanned: true}
+				if err != nil && ctx.Err() == nil {
+					r.err = &FileError{Op: "scan", Path: path, Err: err}
+				}
+				p.results <- r
+			}
+		}()
+	}
+	go func() {
+		defer close(p.done)
+		for r := range p.results {
+			if r.scanned {
+				p.files++
+			}
+			if r.err != nil && onError != nil {
+				onError(r.err)
+			}
+			if onFinding != nil {
+				for _, secret := range r.secrets {
+					onFinding(secret)
+				}
+			}
+		}
+	}()
+	return p
+}
+
+// scan confie un fichier à un worker (bloque si tous sont occupés)
+func (p *filePool) scan(path string) {
+	p.paths <- path
+}
+
+// fail transmet une erreur rencontrée pendant le parcours du répertoire
+func (p *filePool) fail(err *FileError) {
+	p.results <- fileResult{err: err}
+}
+
+// wait attend que tous les résultats aient été transmis et retourne le nombre de fichiers scannés
+func (p *filePool) wait() int {
+	close(p.paths)
+	p.workers.Wait()
+	close(p.results)
+	<-p.done
+	return p.files
+}
+
+// SortSecrets trie des secrets par fichier puis par ligne, en conservant l'ordre
+// de détection des secrets d'une même ligne
+func SortSecrets(secrets []Secret) {
+	sort.SliceStable(secrets, func(i, j int) bool {
+		if secrets[i].File != secrets[j].File {
+			return secrets[i].File < secrets[j].File
+		}
+		return secrets[i].Line < secrets[j].Line
+	})
+}
@@ -0,0 +1,66 @@
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Le résultat ne dépend pas du nombre de workers: secrets triés par chemin puis par ligne
+func TestScanDirectoryDeterministic(t *testing.T) {
+	root := t.TempDir()
+	for i := 0; i < 30; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("svc%d", i%3))
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		content := fmt.Sprintf("# config\nAWS_ACCESS_KEY_ID=AKIAQ7ZKXW3JDN5T%04d\n\nBACKUP_KEY_ID=AKIAQ7ZKXW3JDN5U%04d\n", i, i)
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("app%02d.env", i)), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scanWith := func(concurrency int) []string {
+		opts := DefaultScanOptions()
+		opts.Concurrency = concurrency
+		result, err := ScanDirectory(root, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var found []string
+		for _, s := range result.Secrets {
+			found = append(found, fmt.Sprintf("%s:%d:%s", s.File, s.Line, s.OriginalMatch))
+		}
+		return found
+	}
+
+	sequential := scanWith(1)
+	if len(sequential) != 60 {
+		t.Fatalf("%d secrets, attendu 60", len(sequential))
+	}
+	for i := 1; i < len(sequential); i++ {
+		if sequential[i-1] >= sequential[i] {
+			t.Fatalf("secrets non triés: %s avant %s", sequential[i-1], sequential[i])
+		}
+	}
+	for run := 0; run < 3; run++ {
+		parallel := scanWith(8)
+		if fmt.Sprint(parallel) != fmt.Sprint(sequential) {
+			t.Fatalf("résultat différent avec 8 workers:\n%v\nattendu:\n%v", parallel, sequential)
+		}
+	}
+}
+
+func TestSortSecrets(t *testing.T) {
+	secrets := []Secret{
+		{File: "b.env", Line: 1, RuleID: "b1"},
+		{File: "a.env", Line: 10, RuleID: "a10"},
+		{File: "a.env", Line: 2, RuleID: "a2-first"},
+		{File: "a.env", Line: 2, RuleID: "a2-second"},
+	}
+	SortSecrets(secrets)
+	if got := ruleIDs(secrets); got != "a2-first,a2-second,a10,b1" {
+		t.Errorf("ordre %s, attendu a2-first,a2-second,a10,b1 (tri stable)", got)
+	}
+}